@@ -14,6 +14,25 @@ import (
 )
 
 func main() {
+	// Create context that listens for the interrupt signal from the OS
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "wordbank":
+			if err := runWordBank(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "serve":
+			if err := runServe(ctx, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	fmt.Println("Word Count Service")
 	// Load configuration
 	cfg, err := config.Load()
@@ -22,10 +41,6 @@ func main() {
 	}
 	fmt.Printf("Configuration loaded")
 
-	// Create context that listens for the interrupt signal from the OS
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
 	// Initialize the application
 	fmt.Println("Initializing application...")
 	application, err := app.New(cfg)
@@ -50,3 +65,17 @@ func main() {
 
 	fmt.Println(string(output))
 }
+
+// loadApp loads the configuration and initializes the application, loading
+// its word banks
+func loadApp() (*app.App, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	application, err := app.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize application: %w", err)
+	}
+	return application, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const wordBankUsage = `Usage: wordcount wordbank [-lang code] [-limit n] <command> [argument]
+
+Commands:
+  size               number of words in the word bank
+  contains <word>    whether the word bank contains word
+  prefix <prefix>    words starting with prefix
+  regex <pattern>    words matching a regular expression
+  glob <pattern>     words matching a shell pattern with *, ? and [...]
+  sample <n>         n words chosen at random
+`
+
+// runWordBank loads the configured word banks and answers a query about one
+// of them, printing one word per line
+func runWordBank(args []string) error {
+	flags := flag.NewFlagSet("wordbank", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), wordBankUsage) }
+	lang := flags.String("lang", "", "language of the word bank (default: the default language)")
+	limit := flags.Int("limit", 0, "maximum number of words to print (default: all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	command, arg := flags.Arg(0), flags.Arg(1)
+	argc := map[string]int{"size": 1, "contains": 2, "prefix": 2, "regex": 2, "glob": 2, "sample": 2}[command]
+	if argc == 0 || flags.NArg() != argc {
+		flags.Usage()
+		return fmt.Errorf("invalid wordbank command")
+	}
+
+	application, err := loadApp()
+	if err != nil {
+		return err
+	}
+	// End the line of the word bank progress bar
+	fmt.Println()
+
+	if *lang == "" {
+		info := application.WordBankInfo()
+		if len(info) > 1 {
+			return fmt.Errorf("several word banks are loaded: choose one with -lang")
+		}
+		*lang = info[0].Language
+	}
+	bank, ok := application.WordBanks()[*lang]
+	if !ok {
+		return fmt.Errorf("no word bank for language %q", *lang)
+	}
+
+	var words []string
+	switch command {
+	case "size":
+		fmt.Println(bank.Len())
+		return nil
+	case "contains":
+		fmt.Println(bank.Contains(application.NormalizeWord(arg)))
+		return nil
+	case "prefix":
+		words = bank.Prefix(application.NormalizeWord(arg), *limit)
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		words = bank.Match(re, *limit)
+	case "glob":
+		words, err = bank.Glob(arg, *limit)
+		if err != nil {
+			return fmt.Errorf("invalid glob: %w", err)
+		}
+	case "sample":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid sample size %q: must be a positive integer", arg)
+		}
+		words = bank.Sample(n)
+	}
+
+	for _, word := range words {
+		fmt.Println(word)
+	}
+	return nil
+}
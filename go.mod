@@ -7,6 +7,8 @@ toolchain go1.23.3
 require (
 	github.com/PuerkitoBio/goquery v1.10.0
 	github.com/schollz/progressbar/v3 v3.17.1
+	golang.org/x/net v0.31.0
+	golang.org/x/text v0.21.0
 	golang.org/x/time v0.8.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -15,7 +17,6 @@ require (
 	github.com/andybalholm/cascadia v1.3.2 // indirect
 	github.com/mitchellh/colorstring v0.0.0-20190213212951-d06e56a500db // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/net v0.31.0 // indirect
 	golang.org/x/sys v0.27.0 // indirect
 	golang.org/x/term v0.26.0 // indirect
 )
@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,6 +13,7 @@ import (
 	"github.com/NivBraz/wordcount-service/internal/config"
 	"github.com/NivBraz/wordcount-service/internal/models"
 	"github.com/NivBraz/wordcount-service/pkg/fetcher"
+	"github.com/NivBraz/wordcount-service/pkg/langdetect"
 	"github.com/NivBraz/wordcount-service/pkg/parser"
 	"github.com/NivBraz/wordcount-service/pkg/wordbank"
 	"github.com/schollz/progressbar/v3"
@@ -17,10 +21,16 @@ import (
 
 // App represents the main application
 type App struct {
-	config   *config.Config
-	fetcher  *fetcher.Fetcher
-	parser   *parser.Parser
-	wordBank *wordbank.WordBank
+	config    *config.Config
+	fetcher   *fetcher.Fetcher
+	parser    *parser.Parser
+	languages map[string]*language
+	deny      map[string]struct{}
+	idf       *idfTable
+	variants  map[string]string
+	canonical map[string]struct{}
+	detector  *langdetect.Detector
+	weights   map[parser.Region]int
 }
 
 // New creates a new instance of the application
@@ -38,65 +48,124 @@ func New(cfg *config.Config) (*App, error) {
 		MaxRequestInterval: 5 * time.Second,
 		Timeout:            time.Duration(cfg.HTTPClient.Timeout) * time.Second,
 		UserAgent:          cfg.HTTPClient.UserAgent,
+		MaxBodyBytes:       cfg.Streaming.MaxBodyBytes,
 	}
 
 	// Initialize components
 	f := fetcher.New(fetcherConfig)
-	p := parser.New()
-	wb := wordbank.New()
+	p := parser.NewWithConfig(parser.ParserConfig{
+		Normalization:    cfg.WordProcessing.Normalization,
+		FoldDiacritics:   cfg.WordProcessing.FoldDiacritics,
+		Strategy:         cfg.Extraction.Strategy,
+		DomainStrategies: cfg.Extraction.Domains,
+		Regions:          enabledRegions(cfg),
+	})
+
+	// Load the denylist and the allowlist extending every word bank
+	deny, err := loadWordFiles(p, cfg.Filters.DenyFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load denylist: %w", err)
+	}
+	allow, err := loadWordFiles(p, cfg.Filters.AllowFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load allowlist: %w", err)
+	}
 
-	// Initialize word bank with progress bar
-	wordBankCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Load the variant spellings counted as their canonical term
+	variants, err := loadVariants(p, cfg.Variants.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load variants: %w", err)
+	}
 
-	fmt.Println("Initializing word bank...")
-	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription("Loading word bank..."),
-		progressbar.OptionSetWidth(30),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "=",
-			SaucerHead:    ">",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	// Load the IDF table of earlier runs keywords are scored against
+	idf := newIDFTable()
+	if cfg.Keywords.LoadIDF != "" {
+		idf, err = loadIDFTable(cfg.Keywords.LoadIDF)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load IDF table: %w", err)
+		}
+	}
 
-	if err := initializeWordBank(wordBankCtx, f, p, wb, cfg.URLs.WordBankURL, bar); err != nil {
-		return nil, fmt.Errorf("failed to initialize word bank: %w", err)
+	// Initialize word banks and stop lists, read with a client of their own
+	reader := wordbank.NewReader(wordBankTimeout(cfg), cfg.HTTPClient.UserAgent)
+	if cfg.WordBank.CacheDir != "" {
+		reader.SetCache(wordbank.NewCache(cfg.WordBank.CacheDir))
+	}
+	languages, err := loadLanguages(cfg, reader, p, allow)
+	if err != nil {
+		return nil, err
+	}
+
+	var detector *langdetect.Detector
+	if cfg.Languages.Detect {
+		detector, err = langdetect.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize language detector: %w", err)
+		}
 	}
-	bar.Finish()
 
 	return &App{
-		config:   cfg,
-		fetcher:  f,
-		parser:   p,
-		wordBank: wb,
+		config:    cfg,
+		fetcher:   f,
+		parser:    p,
+		languages: languages,
+		deny:      deny,
+		idf:       idf,
+		variants:  variants,
+		canonical: canonicalTerms(variants),
+		detector:  detector,
+		weights:   regionWeights(cfg),
 	}, nil
 }
 
-// Run executes the main application logic
+// Run executes the main application logic over the configured article URLs,
+// reading them from the URLs file while processing when queue.streamURLs is set
 func (a *App) Run(ctx context.Context) (*models.Result, error) {
-	startTime := time.Now()
+	if a.config.Queue.StreamURLs {
+		r, err := config.OpenURLs(a.config.URLs.ArticleURLsFile)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return a.run(ctx, r, -1)
+	}
+	return a.run(ctx, &sliceSource{urls: a.config.ArticleURLs}, len(a.config.ArticleURLs))
+}
 
-	// Create channels for word processing
-	wordChan := make(chan string, 1000)
-	errChan := make(chan error, len(a.config.ArticleURLs))
+// RunSource is like Run but processes the URLs supplied by src
+func (a *App) RunSource(ctx context.Context, src URLSource) (*models.Result, error) {
+	return a.run(ctx, src, -1)
+}
 
-	// Create wait groups for goroutines
+// run processes the URLs of src with a fixed pool of workers. total is the
+// number of URLs for progress reporting, or -1 if unknown.
+func (a *App) run(ctx context.Context, src URLSource, total int) (*models.Result, error) {
+	startTime := time.Now()
+	retries := a.fetcher.Retries()
+
+	// Create wait group for goroutines
 	var fetchWg sync.WaitGroup
-	var processWg sync.WaitGroup
 
-	// Create word frequency map with mutex
-	frequencies := make(map[string]int)
-	var freqMutex sync.RWMutex
+	// Collect per-article details when requested, and skipped articles always
+	var articles []models.ArticleResult
+	var skipped []models.SkippedArticle
+	var keywordArticles []articleTerms
+	var articlesMutex sync.Mutex
+
+	// Write a record of each article as it completes when requested
+	var records *recordWriter
+	if a.config.ArticleRecords.File != "" {
+		var err error
+		if records, err = createRecordWriter(a.config.ArticleRecords.File); err != nil {
+			return nil, err
+		}
+	}
 
 	// Initialize progress tracking
-	totalArticles := len(a.config.ArticleURLs)
 	var processedArticles int32
 
 	// Create progress bar for article processing
-	bar := progressbar.NewOptions(totalArticles,
+	bar := progressbar.NewOptions(total,
 		progressbar.OptionSetDescription("Processing articles..."),
 		progressbar.OptionSetWidth(30),
 		progressbar.OptionShowCount(),
@@ -109,104 +178,290 @@ func (a *App) Run(ctx context.Context) (*models.Result, error) {
 			BarEnd:        "]",
 		}))
 
-	// Start word processing goroutine
-	processWg.Add(1)
-	go func() {
-		defer processWg.Done()
-		for word := range wordChan {
-			if isValidWord(word) && a.wordBank.Contains(word) {
-				freqMutex.Lock()
-				frequencies[word]++
-				freqMutex.Unlock()
-			}
-		}
-	}()
-
-	// Start fetching articles
-	semaphore := make(chan struct{}, a.config.Concurrency)
-	for _, url := range a.config.ArticleURLs {
+	// Start workers, each counting the articles it fetches into its own
+	// counter and statistics so that no lock is taken per word. Failures are
+	// counted rather than kept, so that memory does not grow with the number
+	// of URLs. The bounded queue makes reading URLs wait for the workers.
+	queueSize := a.config.Queue.Size
+	if queueSize == 0 {
+		queueSize = a.config.Concurrency
+	}
+	urls := make(chan string, queueSize)
+	shards := make([]*counter, a.config.Concurrency)
+	shardStats := make([]runStats, a.config.Concurrency)
+	for i := range shards {
+		shards[i] = a.newCounter()
 		fetchWg.Add(1)
-		go func(url string) {
+		go func(counts *counter, stats *runStats) {
 			defer fetchWg.Done()
-
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			// Fetch and process article
-			if err := a.processArticle(ctx, url, wordChan); err != nil {
-				//log.Printf("Error processing article %s: %v", url, err)
-				errChan <- fmt.Errorf("failed to process %s: %w", url, err)
+			for url := range urls {
+				// Fetch and process article
+				start := time.Now()
+				tokens := counts.tokens
+				article, resp, err := a.processArticle(ctx, url, counts)
+				terms := counts.endArticle()
+				stats.add(resp, err)
+				if records != nil {
+					record := newArticleRecord(url, resp, err, terms, articleRecordTopCount(a.config))
+					record.Tokens = counts.tokens.Read - tokens.Read
+					record.MatchedTokens = counts.tokens.Counted - tokens.Counted
+					record.DurationMs = time.Since(start).Milliseconds()
+					records.write(record)
+				}
+				var skip *skipError
+				if errors.As(err, &skip) {
+					articlesMutex.Lock()
+					skipped = append(skipped, models.SkippedArticle{
+						URL:         url,
+						ContentType: skip.contentType,
+						Reason:      skip.Error(),
+					})
+					articlesMutex.Unlock()
+				} else if err == nil {
+					articlesMutex.Lock()
+					if a.config.Output.IncludeArticles {
+						articles = append(articles, *article)
+					}
+					if a.config.Keywords.Enabled {
+						keywordArticles = append(keywordArticles, articleTerms{url: url, terms: terms})
+					}
+					articlesMutex.Unlock()
+				}
+
+				// Update progress
+				atomic.AddInt32(&processedArticles, 1)
+				bar.Add(1)
 			}
-
-			// Update progress
-			atomic.AddInt32(&processedArticles, 1)
-			bar.Add(1)
-		}(url)
+		}(shards[i], &shardStats[i])
 	}
 
-	// Wait for all fetches to complete and close channels
-	go func() {
-		fetchWg.Wait()
-		close(wordChan)
-		close(errChan)
-		bar.Finish()
-	}()
+	// Feed the queue until the source is exhausted or ctx is done
+	sourceErr := feed(ctx, src, urls)
+	close(urls)
 
-	// Wait for word processing to complete
-	processWg.Wait()
+	// Wait for all fetches to complete
+	fetchWg.Wait()
+	bar.Finish()
 
-	// Check for errors
-	var errs []error
-	for err := range errChan {
-		errs = append(errs, err)
+	// Merge the workers' counts and statistics
+	counts := a.newCounter()
+	for _, shard := range shards {
+		counts.merge(shard)
+	}
+	var stats runStats
+	for i := range shardStats {
+		stats.merge(&shardStats[i])
 	}
 
-	// Prepare results
+	// Prepare results. Errors writing output files are collected and returned
+	// once the result is complete, so that one failed file loses nothing else.
+	var fileErrs []error
+	if records != nil {
+		if err := records.close(); err != nil {
+			fileErrs = append(fileErrs, err)
+		}
+	}
 	result := &models.Result{
-		TopWords: getTopWords(frequencies, 10),
-		Stats: struct {
-			TotalProcessed int `json:"totalProcessed"`
-			TimeElapsed    int `json:"timeElapsedMs"`
-		}{
-			TotalProcessed: len(frequencies),
-			TimeElapsed:    int(time.Since(startTime).Milliseconds()),
-		},
+		TopWords:  a.topWords(counts, 10),
+		Denied:    getTopWords(counts.denied, len(counts.denied)),
+		WordBanks: a.WordBankInfo(),
+		Articles:  articles,
+		Skipped:   skipped,
+	}
+	if a.config.Output.IncludeStats {
+		result.Stats = stats.stats(counts, int(a.fetcher.Retries()-retries), time.Since(startTime))
+	}
+	if a.detector != nil {
+		result.ByLanguage = make(map[string][]models.WordCount, len(counts.languages))
+		for lang, freqs := range counts.languages {
+			result.ByLanguage[lang] = getTopWords(freqs, 10)
+		}
+	}
+	if a.config.Output.SplitByRegion {
+		result.ByRegion = make(map[string][]models.WordCount, len(counts.regions))
+		for region, freqs := range counts.regions {
+			result.ByRegion[string(region)] = getTopWords(freqs, 10)
+		}
+	}
+	if a.config.Output.ByCategory {
+		result.ByCategory = a.groupByAttribute(counts, func(attrs wordbank.Attributes) string { return attrs.Category })
+	}
+	if a.config.Output.ByPartOfSpeech {
+		result.ByPartOfSpeech = a.groupByAttribute(counts, func(attrs wordbank.Attributes) string { return attrs.PartOfSpeech })
+	}
+	if a.config.Output.CompareBaseline {
+		result.Baseline = a.compareBaseline(counts, result.TopWords)
+	}
+	if a.config.Export.File != "" {
+		if err := writeFrequencyTable(a.config.Export.File, exportFormat(a.config), counts); err != nil {
+			fileErrs = append(fileErrs, err)
+		}
+	}
+	if a.config.Variants.Breakdown {
+		result.Variants = make(map[string][]models.WordCount, len(counts.variants))
+		for term, spellings := range counts.variants {
+			result.Variants[term] = getTopWords(spellings, len(spellings))
+		}
+	}
+	if a.config.Keywords.Enabled {
+		idf := a.idf.with(keywordArticles)
+		result.Keywords, result.CorpusKeywords = idf.keywords(keywordArticles, keywordTopCount(a.config))
+		if a.config.Keywords.SaveIDF != "" {
+			if err := idf.save(a.config.Keywords.SaveIDF); err != nil {
+				fileErrs = append(fileErrs, err)
+			}
+		}
+	}
+	if a.config.Fuzzy.Enabled {
+		result.Corrections = getCorrections(counts.corrections)
+	}
+	if a.config.OOV.Track {
+		result.OOV = getTopWords(counts.oov, oovTopCount(a.config))
+		if a.config.OOV.PatchFile != "" {
+			if err := writeOOVPatch(a.config.OOV.PatchFile, counts.oov, a.config.OOV.MinCount); err != nil {
+				fileErrs = append(fileErrs, err)
+			}
+		}
 	}
 
-	if len(errs) > 0 {
-		return result, fmt.Errorf("encountered %d errors during processing", len(errs))
+	if err := errors.Join(fileErrs...); err != nil {
+		return result, err
+	}
+
+	if sourceErr != nil {
+		return result, fmt.Errorf("failed to read article URLs: %w", sourceErr)
+	}
+	if failed := stats.articles.Failed; failed > 0 {
+		return result, fmt.Errorf("encountered %d errors during processing", failed)
 	}
 
 	return result, nil
 }
 
-// processArticle fetches and processes a single article
-func (a *App) processArticle(ctx context.Context, url string, wordChan chan<- string) error {
+// processArticle fetches a single article and counts its words into counts.
+// It also returns the fetched response, if any, when the article fails.
+func (a *App) processArticle(ctx context.Context, url string, counts *counter) (*models.ArticleResult, *fetcher.Response, error) {
+	var resp *fetcher.Response
+	var doc *parser.Document
+	var detected string
+	var err error
+	if a.config.Streaming.Enabled {
+		resp, doc, detected, err = a.streamArticle(ctx, url, counts)
+	} else {
+		resp, doc, detected, err = a.readArticle(ctx, url, counts)
+	}
+
+	var unsupported *parser.UnsupportedTypeError
+	if errors.As(err, &unsupported) {
+		return nil, resp, &skipError{contentType: unsupported.MediaType, err: unsupported}
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &models.ArticleResult{
+		URL:         url,
+		ContentType: doc.ContentType,
+		Encoding:    doc.Encoding,
+		Strategy:    doc.Strategy,
+		Language:    detected,
+		Truncated:   resp.Truncated,
+		Metadata:    doc.Metadata,
+	}, resp, nil
+}
+
+// readArticle downloads a whole article before parsing and counting it
+func (a *App) readArticle(ctx context.Context, url string, counts *counter) (*fetcher.Response, *parser.Document, string, error) {
 	// Fetch article content
-	content, err := a.fetcher.Fetch(ctx, url)
+	resp, err := a.fetcher.FetchResponse(ctx, url)
 	if err != nil {
-		return fmt.Errorf("failed to fetch article: %w", err)
+		return nil, nil, "", fmt.Errorf("failed to fetch article: %w", err)
 	}
 
-	// Parse words from content
-	words, err := a.parser.ParseWords(content)
+	// Decode and parse words from content
+	doc, err := a.parser.ParseDocument(resp.Body, resp.ContentType, url)
 	if err != nil {
-		return fmt.Errorf("failed to parse article: %w", err)
+		return resp, nil, "", fmt.Errorf("failed to parse article: %w", err)
+	}
+
+	// Pick the word bank matching the article's language
+	detected := a.detectLanguage(doc.Words())
+	lang := a.bankLanguage(detected)
+
+	for _, token := range doc.Tokens {
+		a.count(counts, token, lang)
 	}
+	return resp, doc, detected, nil
+}
 
-	// Send words to processing channel
-	for _, word := range words {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case wordChan <- word:
+// streamArticle tokenizes an article while it downloads and counts its words
+// as they are parsed. When language detection is on, the first words are held
+// back until there are enough to detect the language. The words are counted
+// into a counter of the article's own, added to counts only once the whole
+// article was read, so that an article failing mid-download counts nothing.
+func (a *App) streamArticle(ctx context.Context, url string, counts *counter) (*fetcher.Response, *parser.Document, string, error) {
+	article := a.newCounter()
+	var pending []parser.Token
+	var detected, lang string
+	detecting := a.detector != nil
+	if !detecting {
+		detected = a.detectLanguage(nil)
+		lang = a.bankLanguage(detected)
+	}
+
+	// flush detects the language from the held back words and counts them
+	flush := func() {
+		words := make([]string, len(pending))
+		for i, token := range pending {
+			words[i] = token.Word
+		}
+		detected = a.detectLanguage(words)
+		lang = a.bankLanguage(detected)
+		detecting = false
+
+		for _, token := range pending {
+			a.count(article, token, lang)
+		}
+		pending = nil
+	}
+	emit := func(token parser.Token) error {
+		if !detecting {
+			a.count(article, token, lang)
+			return nil
+		}
+		pending = append(pending, token)
+		if len(pending) >= maxDetectionWords {
+			flush()
 		}
+		return nil
 	}
 
-	return nil
+	var doc *parser.Document
+	resp, err := a.fetcher.FetchStream(ctx, url, func(resp *fetcher.Response, body io.Reader) error {
+		var err error
+		doc, err = a.parser.ParseStream(body, resp.ContentType, url, emit)
+		return err
+	})
+	if err != nil {
+		return resp, nil, "", fmt.Errorf("failed to stream article: %w", err)
+	}
+	if detecting {
+		flush()
+	}
+	counts.mergeArticle(article)
+	return resp, doc, detected, nil
+}
+
+// skipError marks an article that was fetched but deliberately not counted
+type skipError struct {
+	contentType string
+	err         error
 }
 
+func (e *skipError) Error() string { return e.err.Error() }
+
+func (e *skipError) Unwrap() error { return e.err }
+
 // Helper functions
 
 func validateConfig(cfg *config.Config) error {
@@ -216,34 +471,146 @@ func validateConfig(cfg *config.Config) error {
 	if cfg.Concurrency <= 0 {
 		return fmt.Errorf("invalid concurrency: must be positive")
 	}
-	if len(cfg.ArticleURLs) == 0 {
+	if len(cfg.ArticleURLs) == 0 && !cfg.Queue.StreamURLs {
 		return fmt.Errorf("no article URLs provided")
 	}
-	if cfg.URLs.WordBankURL == "" {
+	if cfg.URLs.WordBankURL == "" && len(cfg.WordBank.Sources) == 0 {
 		return fmt.Errorf("word bank URL is required")
 	}
 	return nil
 }
 
-func initializeWordBank(ctx context.Context, f *fetcher.Fetcher, p *parser.Parser, wb *wordbank.WordBank, url string, bar *progressbar.ProgressBar) error {
-	// Fetch word bank content
-	content, err := f.Fetch(ctx, url)
+// initializeWordBank reads source and adds its words to wb, with their
+// attributes for a tabular word bank, or expanded with the affix rules of a
+// Hunspell dictionary. With a cache, the words parsed from the same content
+// by an earlier run are reused. It returns a description of each file read.
+func initializeWordBank(ctx context.Context, r *wordbank.Reader, p *parser.Parser, wb *wordbank.WordBank, source wordbank.Source, bar *progressbar.ProgressBar) ([]models.WordBankSource, error) {
+	// Read word bank content
+	list, err := r.Read(ctx, source.Location)
 	if err != nil {
-		return fmt.Errorf("failed to fetch word bank: %w", err)
+		return nil, fmt.Errorf("failed to fetch word bank: %w", err)
+	}
+	infos := []models.WordBankSource{sourceInfo(source.Location, list)}
+
+	format := source.DetectFormat()
+	var affList *wordbank.List
+	if format == wordbank.FormatHunspell {
+		affix := source.AffixLocation()
+		affList, err = r.Read(ctx, affix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch affix file: %w", err)
+		}
+		infos = append(infos, sourceInfo(affix, affList))
 	}
 
-	// Parse words and add to word bank
-	words, err := p.ParseWordBank(content)
-	if err != nil {
-		return fmt.Errorf("failed to parse word bank: %w", err)
+	// A missing or corrupt cache entry is simply parsed again
+	cache := r.Cache()
+	key := parsedKey(p, format, list, affList)
+	var entries []wordbank.Entry
+	if cache != nil {
+		entries, _ = cache.LoadEntries(key)
+	}
+	if entries == nil {
+		entries, err = parseWordBank(p, format, list, affList)
+		if err != nil {
+			return nil, err
+		}
+		if cache != nil {
+			if err := cache.StoreEntries(key, entries); err != nil {
+				infos[0].Warning = joinWarnings(infos[0].Warning, err.Error())
+			}
+		}
 	}
 
-	for _, word := range words {
-		wb.Add(word)
+	tabular := format == wordbank.FormatTSV || format == wordbank.FormatCSV
+	for _, entry := range entries {
+		if tabular {
+			wb.AddEntry(entry.Word, entry.Attributes)
+		} else {
+			wb.Add(entry.Word)
+		}
 		bar.Add(1)
 	}
 
-	return nil
+	return infos, nil
+}
+
+// parseWordBank parses the cleaned words of a word list in format, expanded
+// with the affix rules of affList for a Hunspell dictionary
+func parseWordBank(p *parser.Parser, format string, list, affList *wordbank.List) ([]wordbank.Entry, error) {
+	var words []string
+	switch format {
+	case wordbank.FormatTSV, wordbank.FormatCSV:
+		comma := '\t'
+		if format == wordbank.FormatCSV {
+			comma = ','
+		}
+		parsed, err := wordbank.ParseTable(list.Content, comma)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse word bank: %w", err)
+		}
+		entries := parsed[:0]
+		for _, entry := range parsed {
+			if entry.Word = p.CleanWord(entry.Word); entry.Word != "" {
+				entries = append(entries, entry)
+			}
+		}
+		return entries, nil
+	case wordbank.FormatHunspell:
+		expanded, err := wordbank.ExpandHunspell(list.Content, affList.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Hunspell dictionary: %w", err)
+		}
+		words = p.CleanWords(expanded)
+	default:
+		var err error
+		words, err = p.ParseWordBank(list.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse word bank: %w", err)
+		}
+	}
+
+	entries := make([]wordbank.Entry, len(words))
+	for i, word := range words {
+		entries[i].Word = word
+	}
+	return entries, nil
+}
+
+// parsedKey identifies the words parsed from list, and affList if any, in
+// format with the cleaning settings of p
+func parsedKey(p *parser.Parser, format string, list, affList *wordbank.List) string {
+	key := "parsed:" + format + ":" + p.CleaningKey() + ":" + list.SHA256
+	if affList != nil {
+		key += ":" + affList.SHA256
+	}
+	return key
+}
+
+// sourceInfo describes a word list read from location
+func sourceInfo(location string, list *wordbank.List) models.WordBankSource {
+	version := list.ETag
+	if version == "" {
+		version = list.LastModified
+	}
+	return models.WordBankSource{
+		Location: location,
+		SHA256:   list.SHA256,
+		Version:  version,
+		Origin:   list.Origin,
+		Warning:  joinWarnings(list.FetchError, list.CacheError),
+	}
+}
+
+// joinWarnings joins the non-empty warnings
+func joinWarnings(warnings ...string) string {
+	var nonEmpty []string
+	for _, warning := range warnings {
+		if warning != "" {
+			nonEmpty = append(nonEmpty, warning)
+		}
+	}
+	return strings.Join(nonEmpty, "; ")
 }
 
 func isValidWord(word string) bool {
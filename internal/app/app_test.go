@@ -1,13 +1,24 @@
 package app
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
 )
 
 func TestNew(t *testing.T) {
@@ -184,3 +195,1146 @@ Some words appear more frequently than others in this test.`
 		t.Error("Expected 'test' to be in top words")
 	}
 }
+
+// testConfig returns a minimal valid configuration for tests
+func testConfig(wordBankURL string, articleURLs ...string) *config.Config {
+	cfg := &config.Config{
+		Concurrency: 4,
+		ArticleURLs: articleURLs,
+	}
+	cfg.RateLimit.RequestsPerSecond = 10
+	cfg.RateLimit.Burst = 20
+	cfg.URLs.WordBankURL = wordBankURL
+	cfg.HTTPClient.Timeout = 30
+	cfg.HTTPClient.UserAgent = "test-agent"
+	return cfg
+}
+
+func TestApp_RunLanguages(t *testing.T) {
+	englishArticle := `The new update makes the battery last longer and adds several features that users have been asking for.`
+	spanishArticle := `La nueva actualización hace que la batería dure más y añade varias funciones que los usuarios habían pedido.`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank-en":
+			w.Write([]byte("battery\nupdate\nfeatures\nthe"))
+		case "/wordbank-es":
+			w.Write([]byte("batería\nactualización\nfunciones\nlos"))
+		case "/en":
+			w.Write([]byte(englishArticle))
+		case "/es":
+			w.Write([]byte(spanishArticle))
+		}
+	}))
+	defer server.Close()
+
+	stopWords := filepath.Join(t.TempDir(), "stopwords-es.txt")
+	if err := os.WriteFile(stopWords, []byte("los\n"), 0644); err != nil {
+		t.Fatalf("Failed to create stop words file: %v", err)
+	}
+
+	cfg := testConfig(server.URL+"/wordbank-en", server.URL+"/en", server.URL+"/es")
+	cfg.Languages.Detect = true
+	cfg.Languages.Banks = map[string]config.LanguageConfig{
+		"es": {WordBankURL: server.URL + "/wordbank-es", StopWordsFile: stopWords},
+	}
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := map[string][]string{
+		"en": {"the", "battery", "features", "update"},
+		"es": {"actualización", "batería", "funciones"},
+	}
+	for lang, words := range expected {
+		var got []string
+		for _, wc := range result.ByLanguage[lang] {
+			got = append(got, wc.Word)
+		}
+		if !reflect.DeepEqual(got, words) {
+			t.Errorf("Expected %s words %v, got %v", lang, words, got)
+		}
+	}
+}
+
+func TestApp_RunSkipsUnsupportedContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("release\nproduct"))
+		case "/release.md":
+			w.Header().Set("Content-Type", "text/markdown")
+			w.Write([]byte("# Product Release\n\nThe product ships today."))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/release.md", server.URL+"/logo.png")
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	if len(result.TopWords) == 0 || result.TopWords[0].Word != "product" || result.TopWords[0].Count != 2 {
+		t.Errorf("Expected 'product' counted twice, got %v", result.TopWords)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].URL != server.URL+"/logo.png" || result.Skipped[0].ContentType != "image/png" {
+		t.Errorf("Expected logo.png to be skipped as image/png, got %+v", result.Skipped)
+	}
+}
+
+func TestApp_RunStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("test\ncommon\nwords"))
+		case "/article":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Test</title><script>var test = 1;</script></head>
+<body><p>This test article has some common words.</p><p>The test words repeat in this test.</p></body></html>`))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article", server.URL+"/logo.png")
+	cfg.Streaming.Enabled = true
+	cfg.Languages.Detect = true
+	cfg.Output.IncludeArticles = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []string{"test", "words", "common"}
+	var got []string
+	for _, wc := range result.TopWords {
+		got = append(got, wc.Word)
+	}
+	if !reflect.DeepEqual(got, expected) || result.TopWords[0].Count != 4 {
+		t.Errorf("Expected top words %v with 'test' counted 4 times, got %v", expected, result.TopWords)
+	}
+	if len(result.Articles) != 1 || result.Articles[0].Metadata.Title != "Test" {
+		t.Errorf("Expected one article titled 'Test', got %+v", result.Articles)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].ContentType != "image/png" {
+		t.Errorf("Expected logo.png to be skipped as image/png, got %+v", result.Skipped)
+	}
+}
+
+func TestApp_RunStreamingDiscardsFailedArticles(t *testing.T) {
+	partial := "<html><body><p>test words cut off"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("test\ncommon\nwords"))
+		case "/article":
+			w.Write([]byte("<html><body><p>common words</p></body></html>"))
+		case "/broken":
+			// Promise more than is sent, so that the download fails midway
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Length", "4096")
+			w.Write([]byte(partial))
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article", server.URL+"/broken")
+	cfg.Streaming.Enabled = true
+	cfg.Output.IncludeStats = true
+	cfg.ArticleRecords.File = filepath.Join(t.TempDir(), "articles.ndjson")
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err == nil {
+		t.Fatal("Expected error for the broken article")
+	}
+
+	expected := []models.WordCount{{Word: "common", Count: 1, DocumentFrequency: 1}, {Word: "words", Count: 1, DocumentFrequency: 1}}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v without the broken article's, got %v", expected, result.TopWords)
+	}
+
+	content, err := os.ReadFile(cfg.ArticleRecords.File)
+	if err != nil {
+		t.Fatalf("Failed to read article records: %v", err)
+	}
+	var broken models.ArticleRecord
+	for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+		var record models.ArticleRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to parse article record %q: %v", line, err)
+		}
+		if record.URL == server.URL+"/broken" {
+			broken = record
+		}
+	}
+	if broken.Status != http.StatusOK || broken.Bytes != int64(len(partial)) || broken.Error == "" || broken.MatchedTokens != 0 {
+		t.Errorf("Expected a failed record with status and bytes read, got %+v", broken)
+	}
+	if result.Stats.BytesDownloaded < int64(len(partial)) {
+		t.Errorf("Expected the broken article's bytes in the stats, got %d", result.Stats.BytesDownloaded)
+	}
+}
+
+func TestApp_RunStreamsURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("article\nnumber"))
+		default:
+			w.Write([]byte("Article number " + r.URL.Path[1:]))
+		}
+	}))
+	defer server.Close()
+
+	urlsFile := filepath.Join(t.TempDir(), "urls")
+	lines := "# articles\n"
+	for i := 0; i < 20; i++ {
+		lines += fmt.Sprintf("%s/%d\n\n", server.URL, i)
+	}
+	if err := os.WriteFile(urlsFile, []byte(lines), 0644); err != nil {
+		t.Fatalf("Failed to create URLs file: %v", err)
+	}
+
+	cfg := testConfig(server.URL + "/wordbank")
+	cfg.Concurrency = 3
+	cfg.Queue.Size = 1
+	cfg.Queue.StreamURLs = true
+	cfg.URLs.ArticleURLsFile = urlsFile
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{{Word: "article", Count: 20, DocumentFrequency: 20}, {Word: "number", Count: 20, DocumentFrequency: 20}}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+}
+
+func TestApp_RunWordBankSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/extra":
+			w.Write([]byte("gadget"))
+		case "/article":
+			w.Write([]byte("The gadget has a battery and a screen, and the battery is big."))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	var words bytes.Buffer
+	zw := gzip.NewWriter(&words)
+	zw.Write([]byte("battery\nscreen\nthe\nbig"))
+	zw.Close()
+	vendored := filepath.Join(dir, "words.txt.gz")
+	if err := os.WriteFile(vendored, words.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to create word bank file: %v", err)
+	}
+	excluded := filepath.Join(dir, "excluded.txt")
+	if err := os.WriteFile(excluded, []byte("the\nbig"), 0644); err != nil {
+		t.Fatalf("Failed to create word bank file: %v", err)
+	}
+
+	cfg := testConfig("", server.URL+"/article")
+	cfg.WordBank.Sources = []config.WordBankSource{
+		{Source: vendored},
+		{Source: server.URL + "/extra", Op: "union"},
+		{Source: excluded, Op: "subtract"},
+	}
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{{Word: "battery", Count: 2, DocumentFrequency: 1}, {Word: "gadget", Count: 1, DocumentFrequency: 1}, {Word: "screen", Count: 1, DocumentFrequency: 1}}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+
+	if len(result.WordBanks) != 1 || result.WordBanks[0].Language != "en" || result.WordBanks[0].Words != 3 {
+		t.Fatalf("Expected one English word bank of 3 words, got %+v", result.WordBanks)
+	}
+	var origins []string
+	for _, source := range result.WordBanks[0].Sources {
+		origins = append(origins, source.Origin)
+		if len(source.SHA256) != 64 {
+			t.Errorf("Expected a SHA-256 for %s, got %q", source.Location, source.SHA256)
+		}
+	}
+	if expected := []string{"file", "network", "file"}; !reflect.DeepEqual(origins, expected) {
+		t.Errorf("Expected source origins %v, got %v", expected, origins)
+	}
+}
+
+func TestApp_RunFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("subscribe\nphone\nengadget\nreview"))
+		case "/article":
+			w.Write([]byte("Engadget review: the iPhone is a good phone. Subscribe to Engadget for more."))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	denyFile := filepath.Join(dir, "deny.txt")
+	if err := os.WriteFile(denyFile, []byte("Engadget\nsubscribe\n"), 0644); err != nil {
+		t.Fatalf("Failed to create denylist: %v", err)
+	}
+	allowFile := filepath.Join(dir, "allow.txt")
+	if err := os.WriteFile(allowFile, []byte("iphone\n"), 0644); err != nil {
+		t.Fatalf("Failed to create allowlist: %v", err)
+	}
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article")
+	cfg.Filters.DenyFiles = []string{denyFile}
+	cfg.Filters.AllowFiles = []string{allowFile}
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{{Word: "iphone", Count: 1, DocumentFrequency: 1}, {Word: "phone", Count: 1, DocumentFrequency: 1}, {Word: "review", Count: 1, DocumentFrequency: 1}}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+	expectedDenied := []models.WordCount{{Word: "engadget", Count: 2}, {Word: "subscribe", Count: 1}}
+	if !reflect.DeepEqual(result.Denied, expectedDenied) {
+		t.Errorf("Expected denied words %v, got %v", expectedDenied, result.Denied)
+	}
+}
+
+func TestApp_RunTracksOOV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("the\nnew\nmarket"))
+		case "/article":
+			w.Write([]byte("The new chatbot and the cryptocurrency market: chatbot makers love cryptocurrency and chatbot hype."))
+		}
+	}))
+	defer server.Close()
+
+	stopWords := filepath.Join(t.TempDir(), "stopwords.txt")
+	if err := os.WriteFile(stopWords, []byte("and\n"), 0644); err != nil {
+		t.Fatalf("Failed to create stop words file: %v", err)
+	}
+	patchFile := filepath.Join(t.TempDir(), "oov.txt")
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article")
+	cfg.Languages.Banks = map[string]config.LanguageConfig{"en": {StopWordsFile: stopWords}}
+	cfg.OOV.Track = true
+	cfg.OOV.TopCount = 2
+	cfg.OOV.MinCount = 2
+	cfg.OOV.PatchFile = patchFile
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{{Word: "chatbot", Count: 3}, {Word: "cryptocurrency", Count: 2}}
+	if !reflect.DeepEqual(result.OOV, expected) {
+		t.Errorf("Expected OOV words %v, got %v", expected, result.OOV)
+	}
+
+	patch, err := os.ReadFile(patchFile)
+	if err != nil {
+		t.Fatalf("Failed to read patch file: %v", err)
+	}
+	if string(patch) != "chatbot\ncryptocurrency\n" {
+		t.Errorf("Expected patch of words seen twice, got %q", patch)
+	}
+}
+
+func TestApp_RunHunspellWordBank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Batteries die, a battery dies, and the dead battery is unplugged."))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	dic := filepath.Join(dir, "en.dic")
+	if err := os.WriteFile(dic, []byte("3\nbattery/S\ndie/S\nplug/UD\n"), 0644); err != nil {
+		t.Fatalf("Failed to create dictionary: %v", err)
+	}
+	aff := "PFX U Y 1\nPFX U 0 un .\nSFX S Y 2\nSFX S y ies [^aeiou]y\nSFX S 0 s [^y]\nSFX D Y 1\nSFX D 0 ged g\n"
+	if err := os.WriteFile(filepath.Join(dir, "en.aff"), []byte(aff), 0644); err != nil {
+		t.Fatalf("Failed to create affix file: %v", err)
+	}
+
+	cfg := testConfig("", server.URL)
+	cfg.WordBank.Sources = []config.WordBankSource{{Source: dic}}
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{
+		{Word: "battery", Count: 2, DocumentFrequency: 1},
+		{Word: "batteries", Count: 1, DocumentFrequency: 1},
+		{Word: "die", Count: 1, DocumentFrequency: 1},
+		{Word: "dies", Count: 1, DocumentFrequency: 1},
+		{Word: "unplugged", Count: 1, DocumentFrequency: 1},
+	}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+	if sources := result.WordBanks[0].Sources; len(sources) != 2 || sources[1].Location != filepath.Join(dir, "en.aff") {
+		t.Errorf("Expected the dictionary and its affix file as sources, got %+v", sources)
+	}
+}
+
+func TestApp_RunTabularWordBank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("The battery and screen of the phone: the battery lasts, the screen shines, the phone rings."))
+	}))
+	defer server.Close()
+
+	table := filepath.Join(t.TempDir(), "words.csv")
+	content := "# Tech vocabulary\r\nword,pos,category,frequency\r\nbattery,noun,tech,1\r\nscreen,noun,tech,3\r\nlasts,verb,,2\r\nphone,noun,,\r\n"
+	if err := os.WriteFile(table, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create word bank file: %v", err)
+	}
+
+	cfg := testConfig("", server.URL)
+	cfg.WordBank.Sources = []config.WordBankSource{{Source: table}}
+	cfg.Output.ByCategory = true
+	cfg.Output.ByPartOfSpeech = true
+	cfg.Output.CompareBaseline = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expectedCategories := map[string][]models.WordCount{
+		"tech": {{Word: "battery", Count: 2}, {Word: "screen", Count: 2}},
+	}
+	if !reflect.DeepEqual(result.ByCategory, expectedCategories) {
+		t.Errorf("Expected categories %v, got %v", expectedCategories, result.ByCategory)
+	}
+	expectedPOS := map[string][]models.WordCount{
+		"noun": {{Word: "battery", Count: 2}, {Word: "phone", Count: 2}, {Word: "screen", Count: 2}},
+		"verb": {{Word: "lasts", Count: 1}},
+	}
+	if !reflect.DeepEqual(result.ByPartOfSpeech, expectedPOS) {
+		t.Errorf("Expected parts of speech %v, got %v", expectedPOS, result.ByPartOfSpeech)
+	}
+
+	// 7 words counted against a total frequency of 6
+	expectedBaseline := []models.BaselineComparison{
+		{Word: "battery", Count: 2, Expected: 7.0 / 6, Ratio: 2 / (7.0 / 6)},
+		{Word: "screen", Count: 2, Expected: 3.5, Ratio: 2 / 3.5},
+		{Word: "lasts", Count: 1, Expected: 7.0 / 3, Ratio: 1 / (7.0 / 3)},
+	}
+	if len(result.Baseline) != len(expectedBaseline) {
+		t.Fatalf("Expected baseline %+v, got %+v", expectedBaseline, result.Baseline)
+	}
+	for i, got := range result.Baseline {
+		want := expectedBaseline[i]
+		if got.Word != want.Word || got.Count != want.Count ||
+			math.Abs(got.Expected-want.Expected) > 1e-9 || math.Abs(got.Ratio-want.Ratio) > 1e-9 {
+			t.Errorf("Expected baseline %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestApp_RunFuzzyMatching(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("the\nreceive\nbattery\nnew\nspam"))
+		case "/article":
+			w.Write([]byte("Teh new battery: recieve a batery, receive a battrey, spma and zyzzogeton."))
+		}
+	}))
+	defer server.Close()
+
+	stopWords := filepath.Join(t.TempDir(), "stopwords.txt")
+	if err := os.WriteFile(stopWords, []byte("the\nand\n"), 0644); err != nil {
+		t.Fatalf("Failed to create stop words file: %v", err)
+	}
+	denyFile := filepath.Join(t.TempDir(), "deny.txt")
+	if err := os.WriteFile(denyFile, []byte("spam\n"), 0644); err != nil {
+		t.Fatalf("Failed to create denylist: %v", err)
+	}
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article")
+	cfg.Languages.Banks = map[string]config.LanguageConfig{"en": {StopWordsFile: stopWords}}
+	cfg.Filters.DenyFiles = []string{denyFile}
+	cfg.Fuzzy.Enabled = true
+	cfg.OOV.Track = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{{Word: "battery", Count: 3, DocumentFrequency: 1}, {Word: "receive", Count: 2, DocumentFrequency: 1}, {Word: "new", Count: 1, DocumentFrequency: 1}}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+	expectedCorrections := []models.Correction{
+		{From: "batery", To: "battery", Count: 1},
+		{From: "battrey", To: "battery", Count: 1},
+		{From: "recieve", To: "receive", Count: 1},
+	}
+	if !reflect.DeepEqual(result.Corrections, expectedCorrections) {
+		t.Errorf("Expected corrections %v, got %v", expectedCorrections, result.Corrections)
+	}
+	// "teh" becomes the stop word "the" and "spma" the denied word "spam"
+	if expected := []models.WordCount{{Word: "spam", Count: 1}}; !reflect.DeepEqual(result.Denied, expected) {
+		t.Errorf("Expected denied words %v, got %v", expected, result.Denied)
+	}
+	if expected := []models.WordCount{{Word: "zyzzogeton", Count: 1}}; !reflect.DeepEqual(result.OOV, expected) {
+		t.Errorf("Expected OOV words %v, got %v", expected, result.OOV)
+	}
+}
+
+func TestApp_RunVariants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("color\ntelevision\nemail\nscreen"))
+		case "/article":
+			w.Write([]byte("The colour TV screen: color television, an e-mail about the TV colour, then email."))
+		}
+	}))
+	defer server.Close()
+
+	variants := filepath.Join(t.TempDir(), "variants.csv")
+	content := "# variant,canonical\ncolour,color\nTV\ttelevision\ne-mail,email\n"
+	if err := os.WriteFile(variants, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create variants file: %v", err)
+	}
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/article")
+	cfg.Variants.Files = []string{variants}
+	cfg.Variants.Breakdown = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run app: %v", err)
+	}
+
+	expected := []models.WordCount{
+		{Word: "color", Count: 3, DocumentFrequency: 1},
+		{Word: "television", Count: 3, DocumentFrequency: 1},
+		{Word: "email", Count: 2, DocumentFrequency: 1},
+		{Word: "screen", Count: 1, DocumentFrequency: 1},
+	}
+	if !reflect.DeepEqual(result.TopWords, expected) {
+		t.Errorf("Expected top words %v, got %v", expected, result.TopWords)
+	}
+	expectedVariants := map[string][]models.WordCount{
+		"color":      {{Word: "colour", Count: 2}, {Word: "color", Count: 1}},
+		"television": {{Word: "tv", Count: 2}, {Word: "television", Count: 1}},
+	}
+	if !reflect.DeepEqual(result.Variants, expectedVariants) {
+		t.Errorf("Expected variants %v, got %v", expectedVariants, result.Variants)
+	}
+}
+
+func TestLoadVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", "colour,color\nTV\ttelevision\n", false},
+		{"missing canonical term", "colour\n", true},
+		{"conflicting canonical terms", "colour,color\ncolour,hue\n", true},
+		{"chained variants", "colour,color\ncolor,hue\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "variants.csv")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create variants file: %v", err)
+			}
+			_, err := loadVariants(parser.New(), []string{path})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadVariants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApp_RunExportsFrequencyTable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery\nscreen\nphone"))
+		case "/a":
+			w.Write([]byte("battery battery battery screen"))
+		case "/b":
+			w.Write([]byte("screen phone"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		file     string
+		format   string
+		expected string
+	}{
+		{
+			file:     "table.csv",
+			expected: "word,count,documentFrequency\nbattery,3,1\nscreen,2,2\nphone,1,1\n",
+		},
+		{
+			file: "table.json",
+			expected: `[
+  {"word":"battery","count":3,"documentFrequency":1},
+  {"word":"screen","count":2,"documentFrequency":2},
+  {"word":"phone","count":1,"documentFrequency":1}
+]
+`,
+		},
+		{
+			file:   "table.out",
+			format: "ndjson",
+			expected: `{"word":"battery","count":3,"documentFrequency":1}
+{"word":"screen","count":2,"documentFrequency":2}
+{"word":"phone","count":1,"documentFrequency":1}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			cfg := testConfig(server.URL+"/wordbank", server.URL+"/a", server.URL+"/b")
+			cfg.Export.File = filepath.Join(t.TempDir(), tt.file)
+			cfg.Export.Format = tt.format
+
+			app, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create app: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if _, err := app.Run(ctx); err != nil {
+				t.Fatalf("Failed to run app: %v", err)
+			}
+
+			table, err := os.ReadFile(cfg.Export.File)
+			if err != nil {
+				t.Fatalf("Failed to read frequency table: %v", err)
+			}
+			if string(table) != tt.expected {
+				t.Errorf("Expected frequency table:\n%s\ngot:\n%s", tt.expected, table)
+			}
+		})
+	}
+}
+
+func TestApp_RunCompletesResultOnExportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery"))
+		case "/a":
+			w.Write([]byte("battery gizmo"))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/a")
+	cfg.Export.File = filepath.Join(dir, "missing", "table.csv")
+	cfg.OOV.Track = true
+	cfg.OOV.PatchFile = filepath.Join(dir, "patch.txt")
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err == nil {
+		t.Fatal("Expected error writing the frequency table")
+	}
+	// Sections and files after the failed export are still produced
+	if expected := []models.WordCount{{Word: "gizmo", Count: 1}}; !reflect.DeepEqual(result.OOV, expected) {
+		t.Errorf("Expected OOV words %v, got %v", expected, result.OOV)
+	}
+	if _, err := os.Stat(cfg.OOV.PatchFile); err != nil {
+		t.Errorf("Expected OOV patch file to be written: %v", err)
+	}
+}
+
+func TestApp_RunRanksTopWords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery\nscreen\nphone"))
+		case "/a":
+			w.Write([]byte("battery battery battery battery screen"))
+		case "/b", "/c":
+			w.Write([]byte("screen phone"))
+		}
+	}))
+	defer server.Close()
+
+	battery := models.WordCount{Word: "battery", Count: 4, DocumentFrequency: 1}
+	screen := models.WordCount{Word: "screen", Count: 3, DocumentFrequency: 3}
+	phone := models.WordCount{Word: "phone", Count: 2, DocumentFrequency: 2}
+
+	tests := []struct {
+		name        string
+		ranking     string
+		blendWeight *float64
+		expected    []models.WordCount
+	}{
+		{name: "default", expected: []models.WordCount{battery, screen, phone}},
+		{name: "count", ranking: "count", expected: []models.WordCount{battery, screen, phone}},
+		{name: "document frequency", ranking: "documentFrequency", expected: []models.WordCount{screen, phone, battery}},
+		// battery and phone both score 2, battery wins on count
+		{name: "blend", ranking: "blend", expected: []models.WordCount{screen, battery, phone}},
+		{name: "blend weighted to document frequency", ranking: "blend", blendWeight: weight(0.9), expected: []models.WordCount{screen, phone, battery}},
+		{name: "blend of count only", ranking: "blend", blendWeight: weight(0), expected: []models.WordCount{battery, screen, phone}},
+		{name: "blend of document frequency only", ranking: "blend", blendWeight: weight(1), expected: []models.WordCount{screen, phone, battery}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(server.URL+"/wordbank", server.URL+"/a", server.URL+"/b", server.URL+"/c")
+			cfg.Output.Ranking = tt.ranking
+			cfg.Output.BlendWeight = tt.blendWeight
+
+			app, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create app: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			result, err := app.Run(ctx)
+			if err != nil {
+				t.Fatalf("Failed to run app: %v", err)
+			}
+			if !reflect.DeepEqual(result.TopWords, tt.expected) {
+				t.Errorf("Expected top words %v, got %v", tt.expected, result.TopWords)
+			}
+		})
+	}
+}
+
+func TestApp_RunExtractsKeywords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery\nscreen\nphone"))
+		case "/a":
+			w.Write([]byte("battery battery screen"))
+		case "/b":
+			w.Write([]byte("screen phone phone"))
+		}
+	}))
+	defer server.Close()
+
+	idfFile := filepath.Join(t.TempDir(), "idf.json")
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/a", server.URL+"/b")
+	cfg.Keywords.Enabled = true
+	cfg.Keywords.LoadIDF = idfFile
+	cfg.Keywords.SaveIDF = idfFile
+
+	run := func() *models.Result {
+		app, err := New(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create app: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := app.Run(ctx)
+		if err != nil {
+			t.Fatalf("Failed to run app: %v", err)
+		}
+		return result
+	}
+
+	words := func(keywords []models.Keyword) []string {
+		var result []string
+		for _, k := range keywords {
+			result = append(result, k.Word)
+		}
+		return result
+	}
+
+	// Without a saved table, screen occurring in both articles is the least
+	// distinctive, and battery and phone are equally distinctive of the corpus
+	result := run()
+	if len(result.Keywords) != 2 {
+		t.Fatalf("Expected keywords of 2 articles, got %v", result.Keywords)
+	}
+	expectedArticles := map[string][]string{
+		server.URL + "/a": {"battery", "screen"},
+		server.URL + "/b": {"phone", "screen"},
+	}
+	for _, article := range result.Keywords {
+		if got := words(article.Keywords); !reflect.DeepEqual(got, expectedArticles[article.URL]) {
+			t.Errorf("Expected keywords %v for %s, got %v", expectedArticles[article.URL], article.URL, got)
+		}
+	}
+	if score, expected := result.Keywords[0].Keywords[0].Score, 2.0/3*(math.Log(1.5)+1); math.Abs(score-expected) > 1e-9 {
+		t.Errorf("Expected battery to score %v, got %v", expected, score)
+	}
+	if got, expected := words(result.CorpusKeywords), []string{"battery", "phone", "screen"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected corpus keywords %v, got %v", expected, got)
+	}
+
+	// The second run scores against the saved table and adds its own articles
+	run()
+	table, err := loadIDFTable(idfFile)
+	if err != nil {
+		t.Fatalf("Failed to load IDF table: %v", err)
+	}
+	expectedTable := &idfTable{Documents: 4, DocumentFrequencies: map[string]int{"battery": 2, "screen": 4, "phone": 2}}
+	if !reflect.DeepEqual(table, expectedTable) {
+		t.Errorf("Expected IDF table %+v, got %+v", expectedTable, table)
+	}
+}
+
+func TestApp_RunWritesArticleRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery\nscreen"))
+		case "/old":
+			http.Redirect(w, r, "/a", http.StatusMovedPermanently)
+		case "/a":
+			w.Write([]byte("battery battery screen phone"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/old", server.URL+"/missing")
+	cfg.ArticleRecords.File = filepath.Join(t.TempDir(), "articles.ndjson")
+	cfg.ArticleRecords.TopCount = 1
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The missing article fails the run, but is still recorded
+	if _, err := app.Run(ctx); err == nil {
+		t.Fatal("Expected error for the missing article")
+	}
+
+	content, err := os.ReadFile(cfg.ArticleRecords.File)
+	if err != nil {
+		t.Fatalf("Failed to read article records: %v", err)
+	}
+	records := make(map[string]models.ArticleRecord)
+	for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+		var record models.ArticleRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to parse article record %q: %v", line, err)
+		}
+		records[record.URL] = record
+	}
+
+	got := records[server.URL+"/old"]
+	expected := models.ArticleRecord{
+		URL:           server.URL + "/old",
+		FinalURL:      server.URL + "/a",
+		Status:        http.StatusOK,
+		Bytes:         int64(len("battery battery screen phone")),
+		Tokens:        4,
+		MatchedTokens: 3,
+		TopWords:      []models.WordCount{{Word: "battery", Count: 2}},
+		DurationMs:    got.DurationMs,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected record %+v, got %+v", expected, got)
+	}
+
+	missing := records[server.URL+"/missing"]
+	if missing.Status != http.StatusNotFound || missing.Error == "" || len(missing.TopWords) != 0 {
+		t.Errorf("Expected a failed record with status 404, got %+v", missing)
+	}
+}
+
+func TestApp_RunStats(t *testing.T) {
+	article := "battery battery screen spam gadget an"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery\nscreen\nphone"))
+		case "/a":
+			w.Write([]byte(article))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	denyFile := filepath.Join(t.TempDir(), "deny.txt")
+	if err := os.WriteFile(denyFile, []byte("spam\n"), 0644); err != nil {
+		t.Fatalf("Failed to create denylist: %v", err)
+	}
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/a", server.URL+"/logo.png", server.URL+"/missing")
+	cfg.Filters.DenyFiles = []string{denyFile}
+	cfg.Output.IncludeStats = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err == nil {
+		t.Fatal("Expected error for the missing article")
+	}
+
+	stats := result.Stats
+	if expected := (models.ArticleStats{Attempted: 3, Succeeded: 1, Failed: 1, Skipped: 1}); stats.Articles != expected {
+		t.Errorf("Expected articles %+v, got %+v", expected, stats.Articles)
+	}
+	expectedTokens := models.TokenStats{Read: 6, Valid: 5, NotDenied: 4, NotStopWords: 4, InWordBank: 3, Counted: 3}
+	if stats.Tokens != expectedTokens {
+		t.Errorf("Expected tokens %+v, got %+v", expectedTokens, stats.Tokens)
+	}
+	if stats.DistinctWords != 2 {
+		t.Errorf("Expected 2 distinct words, got %d", stats.DistinctWords)
+	}
+	if expected := int64(len(article) + len("\x89PNG\r\n\x1a\n")); stats.BytesDownloaded != expected {
+		t.Errorf("Expected %d bytes downloaded, got %d", expected, stats.BytesDownloaded)
+	}
+	// The missing article is retried once before failing
+	if stats.Retries != 1 {
+		t.Errorf("Expected 1 retry, got %d", stats.Retries)
+	}
+	if latency := stats.FetchLatency; latency.Max <= 0 || latency.P50 > latency.P99 || latency.P99 > latency.Max {
+		t.Errorf("Expected ordered positive latencies, got %+v", latency)
+	}
+	if stats.ArticlesPerSecond <= 0 {
+		t.Errorf("Expected a positive throughput, got %v", stats.ArticlesPerSecond)
+	}
+
+	cfg.Output.IncludeStats = false
+	if result, _ := app.Run(ctx); result.Stats != nil {
+		t.Errorf("Expected no stats when not included, got %+v", result.Stats)
+	}
+}
+
+func TestApp_RunCompletesResultOnIDFSaveError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery"))
+		case "/a":
+			w.Write([]byte("battery gizmo"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/a")
+	cfg.Keywords.Enabled = true
+	cfg.Keywords.SaveIDF = filepath.Join(t.TempDir(), "missing", "idf.json")
+	cfg.OOV.Track = true
+
+	app, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := app.Run(ctx)
+	if err == nil {
+		t.Fatal("Expected error saving the IDF table")
+	}
+	if len(result.Keywords) != 1 || len(result.OOV) != 1 {
+		t.Errorf("Expected keywords and OOV words despite the error, got %v and %v", result.Keywords, result.OOV)
+	}
+}
+
+func TestApp_RunReusesParsedWordBank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wordbank":
+			w.Write([]byte("battery"))
+		case "/a":
+			w.Write([]byte("battery gadget"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL+"/wordbank", server.URL+"/a")
+	cfg.WordBank.CacheDir = t.TempDir()
+
+	run := func() []models.WordCount {
+		app, err := New(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create app: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := app.Run(ctx)
+		if err != nil {
+			t.Fatalf("Failed to run app: %v", err)
+		}
+		return result.TopWords
+	}
+
+	if got, expected := run(), []models.WordCount{{Word: "battery", Count: 1, DocumentFrequency: 1}}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected top words %v, got %v", expected, got)
+	}
+
+	// Replace the parsed words cached by the first run, which the second run
+	// then uses instead of parsing the unchanged list again
+	list, err := wordbank.NewReader(5*time.Second, "").Read(context.Background(), server.URL+"/wordbank")
+	if err != nil {
+		t.Fatalf("Failed to read word bank: %v", err)
+	}
+	key := parsedKey(parser.New(), wordbank.FormatList, list, nil)
+	if err := wordbank.NewCache(cfg.WordBank.CacheDir).StoreEntries(key, []wordbank.Entry{{Word: "gadget"}}); err != nil {
+		t.Fatalf("Failed to store cached words: %v", err)
+	}
+	if got, expected := run(), []models.WordCount{{Word: "gadget", Count: 1, DocumentFrequency: 1}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected top words %v from the cached words, got %v", expected, got)
+	}
+}
+
+// weight returns a pointer to a blend weight
+func weight(w float64) *float64 {
+	return &w
+}
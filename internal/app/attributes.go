@@ -0,0 +1,70 @@
+package app
+
+import (
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
+)
+
+// groupByAttribute groups the counted words by an attribute they have in
+// their language's word bank, returning the top words of each group. Words
+// without the attribute are left out.
+func (a *App) groupByAttribute(counts *counter, attribute func(wordbank.Attributes) string) map[string][]models.WordCount {
+	groups := make(map[string]map[string]int)
+	for lang, freqs := range counts.languages {
+		bank := a.languages[lang].wordBank
+		for word, count := range freqs {
+			attrs, ok := bank.Attributes(word)
+			if !ok {
+				continue
+			}
+			if key := attribute(attrs); key != "" {
+				addTo(groups, key, word, count)
+			}
+		}
+	}
+
+	result := make(map[string][]models.WordCount, len(groups))
+	for key, freqs := range groups {
+		result[key] = getTopWords(freqs, 10)
+	}
+	return result
+}
+
+// compareBaseline compares the counts of words with the counts their word
+// bank frequencies predict. A word's expected count in a language is its
+// share of the word bank's total frequency times the number of words counted
+// in that language. Words without a frequency are left out.
+func (a *App) compareBaseline(counts *counter, words []models.WordCount) []models.BaselineComparison {
+	totals := make(map[string]int, len(counts.languages))
+	for lang, freqs := range counts.languages {
+		for _, count := range freqs {
+			totals[lang] += count
+		}
+	}
+
+	var comparisons []models.BaselineComparison
+	for _, wc := range words {
+		var expected float64
+		for lang, freqs := range counts.languages {
+			if freqs[wc.Word] == 0 {
+				continue
+			}
+			bank := a.languages[lang].wordBank
+			attrs, ok := bank.Attributes(wc.Word)
+			if !ok || attrs.Frequency == 0 {
+				continue
+			}
+			expected += attrs.Frequency / bank.TotalFrequency() * float64(totals[lang])
+		}
+		if expected == 0 {
+			continue
+		}
+		comparisons = append(comparisons, models.BaselineComparison{
+			Word:     wc.Word,
+			Count:    wc.Count,
+			Expected: expected,
+			Ratio:    float64(wc.Count) / expected,
+		})
+	}
+	return comparisons
+}
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"sort"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+)
+
+// counter tallies word frequencies overall, per language and optionally per
+// region. It is not safe for concurrent use: each worker counts into its own
+// counter, and the workers' counters are merged once they are done.
+type counter struct {
+	total map[string]int
+	// docs holds the number of articles each word was counted in
+	docs map[string]int
+	// article holds the counts of the article being processed
+	article map[string]int
+	// tokens counts the tokens filtered at each stage
+	tokens      models.TokenStats
+	denied      map[string]int
+	oov         map[string]int
+	corrections map[correction]int
+	// variants maps canonical terms to the counts of their spellings
+	variants  map[string]map[string]int
+	languages map[string]map[string]int
+	regions   map[parser.Region]map[string]int
+}
+
+// newCounter creates an empty counter, tracking regions when splitRegions is
+// set and out-of-vocabulary words when trackOOV is set
+func newCounter(splitRegions, trackOOV bool) *counter {
+	c := &counter{
+		total:       make(map[string]int),
+		docs:        make(map[string]int),
+		article:     make(map[string]int),
+		denied:      make(map[string]int),
+		corrections: make(map[correction]int),
+		languages:   make(map[string]map[string]int),
+	}
+	if splitRegions {
+		c.regions = make(map[parser.Region]map[string]int)
+	}
+	if trackOOV {
+		c.oov = make(map[string]int)
+	}
+	return c
+}
+
+// correction is a misspelled word and the word bank word it was counted as
+type correction struct {
+	from, to string
+}
+
+// newCounter creates an empty counter tracking what the configuration asks for
+func (a *App) newCounter() *counter {
+	c := newCounter(a.config.Output.SplitByRegion, a.config.OOV.Track)
+	if a.config.Variants.Breakdown {
+		c.variants = make(map[string]map[string]int)
+	}
+	return c
+}
+
+// add counts weight occurrences of word
+func (c *counter) add(word, lang string, region parser.Region, weight int) {
+	c.total[word] += weight
+	c.article[word] += weight
+	c.tokens.Counted++
+	addTo(c.languages, lang, word, weight)
+	if c.regions != nil {
+		addTo(c.regions, region, word, weight)
+	}
+}
+
+// endArticle records that the words counted since the last call occurred in
+// one more article, and returns their counts in that article
+func (c *counter) endArticle() map[string]int {
+	terms := c.article
+	for word := range terms {
+		c.docs[word]++
+	}
+	c.article = make(map[string]int)
+	return terms
+}
+
+// merge adds the counts of other to c
+func (c *counter) merge(other *counter) {
+	for word, count := range other.total {
+		c.total[word] += count
+	}
+	addTokenStats(&c.tokens, other.tokens)
+	for word, count := range other.docs {
+		c.docs[word] += count
+	}
+	for word, count := range other.denied {
+		c.denied[word] += count
+	}
+	if c.variants != nil {
+		for term, spellings := range other.variants {
+			for spelling, count := range spellings {
+				addTo(c.variants, term, spelling, count)
+			}
+		}
+	}
+	for corr, count := range other.corrections {
+		c.corrections[corr] += count
+	}
+	if c.oov != nil {
+		for word, count := range other.oov {
+			c.oov[word] += count
+		}
+	}
+	for lang, freqs := range other.languages {
+		for word, count := range freqs {
+			addTo(c.languages, lang, word, count)
+		}
+	}
+	if c.regions != nil {
+		for region, freqs := range other.regions {
+			for word, count := range freqs {
+				addTo(c.regions, region, word, count)
+			}
+		}
+	}
+}
+
+// mergeArticle adds the counts of other, holding words of the article being
+// processed, to c as part of that article
+func (c *counter) mergeArticle(other *counter) {
+	c.merge(other)
+	for word, count := range other.article {
+		c.article[word] += count
+	}
+}
+
+// addTo adds count to word in the frequency map of key, creating it if needed
+func addTo[K comparable](freqs map[K]map[string]int, key K, word string, count int) {
+	m := freqs[key]
+	if m == nil {
+		m = make(map[string]int)
+		freqs[key] = m
+	}
+	m[word] += count
+}
+
+// count filters a word of an article in lang and adds it to c with its
+// region's weight. Variant spellings are counted as their canonical term, and
+// words missing from the word bank as the closest word bank word when fuzzy
+// matching is on. Denied words, corrections and words still missing from the
+// word bank when tracked are tallied separately, once per occurrence.
+func (a *App) count(c *counter, token parser.Token, lang string) {
+	c.tokens.Read++
+	spelling := token.Word
+	if canonical, ok := a.variants[token.Word]; ok {
+		token.Word = canonical
+	}
+	if !isValidWord(token.Word) {
+		return
+	}
+	c.tokens.Valid++
+	if _, denied := a.deny[token.Word]; denied {
+		c.denied[token.Word]++
+		return
+	}
+	c.tokens.NotDenied++
+	l := a.languages[lang]
+	if l.isStopWord(token.Word) {
+		return
+	}
+	c.tokens.NotStopWords++
+	if l.accepts(token.Word) {
+		c.tokens.InWordBank++
+		weight := a.regionWeight(token.Region)
+		c.add(token.Word, lang, token.Region, weight)
+		if _, ok := a.canonical[token.Word]; ok && c.variants != nil {
+			addTo(c.variants, token.Word, spelling, weight)
+		}
+		return
+	}
+	if corrected, ok := l.correct(token.Word); ok {
+		// The correction goes through the same filters as the words counted as is
+		if _, denied := a.deny[corrected]; denied {
+			c.denied[corrected]++
+		} else if !l.isStopWord(corrected) {
+			c.tokens.Corrected++
+			c.add(corrected, lang, token.Region, a.regionWeight(token.Region))
+			c.corrections[correction{from: token.Word, to: corrected}]++
+		}
+		return
+	}
+	if c.oov != nil {
+		c.oov[token.Word]++
+	}
+}
+
+// getCorrections lists the corrections applied, most frequent first
+func getCorrections(corrections map[correction]int) []models.Correction {
+	result := make([]models.Correction, 0, len(corrections))
+	for corr, count := range corrections {
+		result = append(result, models.Correction{From: corr.from, To: corr.to, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].From < result[j].From
+	})
+	return result
+}
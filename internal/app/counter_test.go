@@ -0,0 +1,203 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
+)
+
+func TestCounterMerge(t *testing.T) {
+	a := newCounter(true, false)
+	a.add("battery", "en", parser.RegionBody, 1)
+	a.add("update", "en", parser.RegionTitle, 2)
+
+	b := newCounter(true, false)
+	b.add("battery", "en", parser.RegionTitle, 2)
+	b.add("batería", "es", parser.RegionBody, 1)
+
+	a.merge(b)
+
+	if want := map[string]int{"battery": 3, "update": 2, "batería": 1}; !reflect.DeepEqual(a.total, want) {
+		t.Errorf("total = %v, want %v", a.total, want)
+	}
+	if want := map[string]int{"battery": 3, "update": 2}; !reflect.DeepEqual(a.languages["en"], want) {
+		t.Errorf("languages[en] = %v, want %v", a.languages["en"], want)
+	}
+	if want := map[string]int{"update": 2, "battery": 2}; !reflect.DeepEqual(a.regions[parser.RegionTitle], want) {
+		t.Errorf("regions[title] = %v, want %v", a.regions[parser.RegionTitle], want)
+	}
+
+	a.endArticle()
+	b.endArticle()
+	b.add("battery", "en", parser.RegionBody, 1)
+	b.endArticle()
+	d := newCounter(false, false)
+	d.merge(a)
+	d.merge(b)
+	if want := map[string]int{"battery": 3, "update": 1, "batería": 1}; !reflect.DeepEqual(d.docs, want) {
+		t.Errorf("docs = %v, want %v", d.docs, want)
+	}
+
+	c := newCounter(false, false)
+	c.merge(b)
+	if c.regions != nil {
+		t.Errorf("regions = %v, want nil when not splitting by region", c.regions)
+	}
+}
+
+func TestCountingDesignsAgree(t *testing.T) {
+	a, articles := benchmarkCorpus(50, 200)
+	// The channel design predates document frequencies, so only the counts
+	// it kept are compared
+	got, want := countSharded(a, articles, 4), countViaChannel(a, articles, 4)
+	if !reflect.DeepEqual(got.total, want.total) || !reflect.DeepEqual(got.languages, want.languages) ||
+		!reflect.DeepEqual(got.regions, want.regions) {
+		t.Errorf("sharded counts differ from channel counts")
+	}
+}
+
+// Benchmarks compare counting into worker-local counters merged at the end
+// against the previous design, where every word went through one channel to
+// a single goroutine taking a mutex per word.
+
+func BenchmarkCounting(b *testing.B) {
+	a, articles := benchmarkCorpus(200, 1000)
+	tokens := 0
+	for _, article := range articles {
+		tokens += len(article)
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("channel/workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				countViaChannel(a, articles, workers)
+			}
+			b.ReportMetric(float64(tokens*b.N)/b.Elapsed().Seconds(), "words/s")
+		})
+		b.Run(fmt.Sprintf("sharded/workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				countSharded(a, articles, workers)
+			}
+			b.ReportMetric(float64(tokens*b.N)/b.Elapsed().Seconds(), "words/s")
+		})
+	}
+}
+
+// benchmarkCorpus returns an app whose word bank holds half of a synthetic
+// vocabulary, and articles of random words from that vocabulary
+func benchmarkCorpus(articleCount, articleLen int) (*App, [][]parser.Token) {
+	rng := rand.New(rand.NewSource(1))
+	vocabulary := make([]string, 10000)
+	wb := wordbank.New()
+	for i := range vocabulary {
+		word := make([]byte, 3+rng.Intn(8))
+		for j := range word {
+			word[j] = byte('a' + rng.Intn(26))
+		}
+		vocabulary[i] = string(word)
+		if i%2 == 0 {
+			wb.Add(vocabulary[i])
+		}
+	}
+
+	regions := []parser.Region{parser.RegionBody, parser.RegionBody, parser.RegionBody, parser.RegionTitle}
+	articles := make([][]parser.Token, articleCount)
+	for i := range articles {
+		articles[i] = make([]parser.Token, articleLen)
+		for j := range articles[i] {
+			articles[i][j] = parser.Token{
+				Word:   vocabulary[rng.Intn(len(vocabulary))],
+				Region: regions[rng.Intn(len(regions))],
+			}
+		}
+	}
+
+	cfg := testConfig("")
+	cfg.Output.SplitByRegion = true
+	a := &App{
+		config:    cfg,
+		languages: map[string]*language{"en": {wordBank: wb.Freeze()}},
+		weights:   map[parser.Region]int{parser.RegionTitle: 2},
+	}
+	return a, articles
+}
+
+// countSharded counts articles on workers goroutines the way Run does
+func countSharded(a *App, articles [][]parser.Token, workers int) *counter {
+	queue := make(chan []parser.Token)
+	shards := make([]*counter, workers)
+	var wg sync.WaitGroup
+	for i := range shards {
+		shards[i] = a.newCounter()
+		wg.Add(1)
+		go func(counts *counter) {
+			defer wg.Done()
+			for article := range queue {
+				for _, token := range article {
+					a.count(counts, token, "en")
+				}
+				counts.endArticle()
+			}
+		}(shards[i])
+	}
+	for _, article := range articles {
+		queue <- article
+	}
+	close(queue)
+	wg.Wait()
+
+	counts := a.newCounter()
+	for _, shard := range shards {
+		counts.merge(shard)
+	}
+	return counts
+}
+
+// countViaChannel counts articles the way Run did before per-worker
+// counting, sending every word to a single consumer goroutine
+func countViaChannel(a *App, articles [][]parser.Token, workers int) *counter {
+	type articleWord struct {
+		word     string
+		language string
+		region   parser.Region
+	}
+
+	counts := a.newCounter()
+	var mu sync.RWMutex
+	wordChan := make(chan articleWord, 1000)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for aw := range wordChan {
+			if isValidWord(aw.word) && a.languages[aw.language].accepts(aw.word) {
+				weight := a.regionWeight(aw.region)
+				mu.Lock()
+				counts.add(aw.word, aw.language, aw.region, weight)
+				mu.Unlock()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, workers)
+	for _, article := range articles {
+		wg.Add(1)
+		go func(article []parser.Token) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			for _, token := range article {
+				wordChan <- articleWord{word: token.Word, language: "en", region: token.Region}
+			}
+		}(article)
+	}
+	wg.Wait()
+	close(wordChan)
+	<-done
+	return counts
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/internal/models"
+)
+
+// exportFormat returns the format of the frequency table export: the
+// configured one, otherwise the one the file extension stands for, CSV if none
+func exportFormat(cfg *config.Config) string {
+	if cfg.Export.Format != "" {
+		return cfg.Export.Format
+	}
+	switch filepath.Ext(cfg.Export.File) {
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	}
+	return "csv"
+}
+
+// writeFrequencyTable writes every counted word with its count and document
+// frequency to path, most frequent first. Only the words are sorted, their
+// counts being looked up as each row is written, so the table is streamed to
+// the file rather than built in memory first.
+func writeFrequencyTable(path, format string, counts *counter) error {
+	words := make([]string, 0, len(counts.total))
+	for word := range counts.total {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		ci, cj := counts.total[words[i]], counts.total[words[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return words[i] < words[j]
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create frequency table: %w", err)
+	}
+	w := bufio.NewWriter(file)
+
+	row := func(word string) models.WordCount {
+		return models.WordCount{Word: word, Count: counts.total[word], DocumentFrequency: counts.docs[word]}
+	}
+	switch format {
+	case "csv":
+		err = writeFrequencyCSV(w, words, row)
+	case "json":
+		err = writeFrequencyJSON(w, words, row)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, word := range words {
+			if err = enc.Encode(row(word)); err != nil {
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write frequency table: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write frequency table: %w", err)
+	}
+	return nil
+}
+
+func writeFrequencyCSV(w *bufio.Writer, words []string, row func(string) models.WordCount) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"word", "count", "documentFrequency"}); err != nil {
+		return err
+	}
+	for _, word := range words {
+		r := row(word)
+		if err := cw.Write([]string{r.Word, strconv.Itoa(r.Count), strconv.Itoa(r.DocumentFrequency)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeFrequencyJSON writes the rows as a JSON array, one row per line
+func writeFrequencyJSON(w *bufio.Writer, words []string, row func(string) models.WordCount) error {
+	w.WriteString("[")
+	for i, word := range words {
+		data, err := json.Marshal(row(word))
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n  ")
+		w.Write(data)
+	}
+	_, err := w.WriteString("\n]\n")
+	return err
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/internal/models"
+)
+
+// articleTerms holds the counts of the words counted in an article
+type articleTerms struct {
+	url   string
+	terms map[string]int
+}
+
+// idfTable holds the number of articles each word occurred in, out of how
+// many, from which inverse document frequencies are computed. It is saved as
+// JSON so that later runs can score their keywords against a larger corpus
+// than their own articles.
+type idfTable struct {
+	Documents           int            `json:"documents"`
+	DocumentFrequencies map[string]int `json:"documentFrequencies"`
+}
+
+func newIDFTable() *idfTable {
+	return &idfTable{DocumentFrequencies: make(map[string]int)}
+}
+
+// loadIDFTable reads an IDF table saved by an earlier run. A missing file is
+// an empty table, so that a run can load and save the same file from the
+// first time on.
+func loadIDFTable(path string) (*idfTable, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newIDFTable(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	t := newIDFTable()
+	if err := json.Unmarshal(content, t); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if t.DocumentFrequencies == nil {
+		t.DocumentFrequencies = make(map[string]int)
+	}
+	return t, nil
+}
+
+// with returns a copy of t also counting articles
+func (t *idfTable) with(articles []articleTerms) *idfTable {
+	result := &idfTable{
+		Documents:           t.Documents + len(articles),
+		DocumentFrequencies: maps.Clone(t.DocumentFrequencies),
+	}
+	for _, article := range articles {
+		for word := range article.terms {
+			result.DocumentFrequencies[word]++
+		}
+	}
+	return result
+}
+
+// idf returns the smoothed inverse document frequency of word, which is 1 for
+// a word occurring in every article and grows as the word gets rarer
+func (t *idfTable) idf(word string) float64 {
+	return math.Log(float64(1+t.Documents)/float64(1+t.DocumentFrequencies[word])) + 1
+}
+
+// save writes t to path
+func (t *idfTable) save(path string) error {
+	content, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode IDF table: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write IDF table: %w", err)
+	}
+	return nil
+}
+
+// keywords scores the words of each article by TF-IDF: their share of the
+// article's counted words times their inverse document frequency. It returns
+// the n best keywords of each article, ordered by URL, and the n words with
+// the highest mean score over all articles as the corpus keywords.
+func (t *idfTable) keywords(articles []articleTerms, n int) ([]models.ArticleKeywords, []models.Keyword) {
+	var result []models.ArticleKeywords
+	corpus := make(map[string]float64)
+	for _, article := range articles {
+		total := 0
+		for _, count := range article.terms {
+			total += count
+		}
+		if total == 0 {
+			continue
+		}
+
+		scores := make(map[string]float64, len(article.terms))
+		for word, count := range article.terms {
+			score := float64(count) / float64(total) * t.idf(word)
+			scores[word] = score
+			corpus[word] += score / float64(len(articles))
+		}
+		result = append(result, models.ArticleKeywords{URL: article.url, Keywords: topKeywords(scores, n)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].URL < result[j].URL })
+	return result, topKeywords(corpus, n)
+}
+
+// topKeywords returns the n best scored words, ties going to the first
+// alphabetically
+func topKeywords(scores map[string]float64, n int) []models.Keyword {
+	keywords := make([]models.Keyword, 0, len(scores))
+	for word, score := range scores {
+		keywords = append(keywords, models.Keyword{Word: word, Score: score})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Word < keywords[j].Word
+	})
+	if len(keywords) > n {
+		return keywords[:n]
+	}
+	return keywords
+}
+
+// keywordTopCount returns how many keywords to report per article and for
+// the corpus
+func keywordTopCount(cfg *config.Config) int {
+	if cfg.Keywords.TopCount <= 0 {
+		return 10
+	}
+	return cfg.Keywords.TopCount
+}
@@ -0,0 +1,318 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
+	"github.com/schollz/progressbar/v3"
+)
+
+// maxDetectionWords caps how much of an article is used for language detection
+const maxDetectionWords = 500
+
+// language holds the word bank and stop list used to filter words of one
+// language, and the allowlist shared by all languages
+type language struct {
+	wordBank  *wordbank.Snapshot
+	stopWords map[string]struct{}
+	allow     map[string]struct{}
+	info      models.WordBankInfo
+	// fuzzy maps misspelled words to word bank words when fuzzy matching is on
+	fuzzy *fuzzyMatcher
+}
+
+// maxCachedCorrections bounds the fuzzy matcher's cache, beyond which words
+// are looked up every time they come up
+const maxCachedCorrections = 1 << 16
+
+// fuzzyMatcher corrects words missing from a word bank to the closest word
+// it contains, remembering its answers since the same misspellings keep
+// coming up
+type fuzzyMatcher struct {
+	index         *wordbank.FuzzyIndex
+	maxDistance   int
+	minWordLength int
+	// cache maps up to about maxCachedCorrections words to their correction,
+	// "" if there is none
+	cache     sync.Map
+	cacheSize atomic.Int64
+}
+
+func (l *language) accepts(word string) bool {
+	if l.isStopWord(word) {
+		return false
+	}
+	if _, allowed := l.allow[word]; allowed {
+		return true
+	}
+	return l.wordBank.Contains(word)
+}
+
+func (l *language) isStopWord(word string) bool {
+	_, stop := l.stopWords[word]
+	return stop
+}
+
+// correct returns the word bank word closest to a word the language doesn't
+// accept, if fuzzy matching is on and one is close enough
+func (l *language) correct(word string) (string, bool) {
+	if l.fuzzy == nil || utf8.RuneCountInString(word) < l.fuzzy.minWordLength {
+		return "", false
+	}
+	if corrected, ok := l.fuzzy.cache.Load(word); ok {
+		return corrected.(string), corrected != ""
+	}
+	corrected, _, _ := l.fuzzy.index.Closest(word, l.fuzzy.maxDistance)
+	if l.fuzzy.cacheSize.Load() < maxCachedCorrections {
+		if _, loaded := l.fuzzy.cache.LoadOrStore(word, corrected); !loaded {
+			l.fuzzy.cacheSize.Add(1)
+		}
+	}
+	return corrected, corrected != ""
+}
+
+// defaultLanguage returns the language used when detection is off or inconclusive
+func defaultLanguage(cfg *config.Config) string {
+	if cfg.Languages.Default == "" {
+		return "en"
+	}
+	return cfg.Languages.Default
+}
+
+// loadLanguages loads the word bank and stop list of the default language and,
+// when detection is enabled, of every configured language
+func loadLanguages(cfg *config.Config, r *wordbank.Reader, p *parser.Parser, allow map[string]struct{}) (map[string]*language, error) {
+	defaultLang := defaultLanguage(cfg)
+	banks := map[string]config.LanguageConfig{
+		defaultLang: cfg.Languages.Banks[defaultLang],
+	}
+	if cfg.Languages.Detect {
+		for lang, bank := range cfg.Languages.Banks {
+			banks[lang] = bank
+		}
+	}
+
+	// Load in a stable order so the progress output is predictable
+	langs := make([]string, 0, len(banks))
+	for lang := range banks {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	languages := make(map[string]*language, len(banks))
+	for _, lang := range langs {
+		bank := banks[lang]
+		wb, info, err := loadWordBank(r, p, wordBankSources(cfg, bank), lang, wordBankTimeout(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize word bank for %q: %w", lang, err)
+		}
+
+		stopWords, err := loadStopWords(p, bank.StopWordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load stop words for %q: %w", lang, err)
+		}
+
+		languages[lang] = &language{
+			wordBank:  wb,
+			stopWords: stopWords,
+			allow:     allow,
+			info:      info,
+		}
+		if cfg.Fuzzy.Enabled {
+			languages[lang].fuzzy = newFuzzyMatcher(cfg, wb)
+		}
+	}
+
+	return languages, nil
+}
+
+// newFuzzyMatcher indexes the words of wb for fuzzy matching
+func newFuzzyMatcher(cfg *config.Config, wb *wordbank.Snapshot) *fuzzyMatcher {
+	maxDistance := cfg.Fuzzy.MaxDistance
+	if maxDistance == 0 {
+		maxDistance = 1
+	}
+	return &fuzzyMatcher{
+		index:         wordbank.NewFuzzyIndex(wb, maxDistance),
+		maxDistance:   maxDistance,
+		minWordLength: cfg.Fuzzy.MinWordLength,
+	}
+}
+
+// wordBankSources returns the sources of a language's word bank, falling
+// back to the sources shared by all languages and then to urls.wordBankURL
+func wordBankSources(cfg *config.Config, bank config.LanguageConfig) []wordbank.Source {
+	switch {
+	case len(bank.WordBankSources) > 0:
+		return toSources(bank.WordBankSources)
+	case bank.WordBankURL != "":
+		return []wordbank.Source{{Location: bank.WordBankURL}}
+	case len(cfg.WordBank.Sources) > 0:
+		return toSources(cfg.WordBank.Sources)
+	}
+	return []wordbank.Source{{Location: cfg.URLs.WordBankURL}}
+}
+
+func toSources(sources []config.WordBankSource) []wordbank.Source {
+	result := make([]wordbank.Source, len(sources))
+	for i, source := range sources {
+		result[i] = wordbank.Source{Location: source.Source, Op: wordbank.Op(source.Op), Format: source.Format, Affix: source.Affix}
+	}
+	return result
+}
+
+// wordBankTimeout returns how long loading a word bank may take
+func wordBankTimeout(cfg *config.Config) time.Duration {
+	if cfg.WordBank.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(cfg.WordBank.Timeout) * time.Second
+}
+
+// loadWordBank reads, parses and combines the sources of a single word bank
+// with a progress bar, returning a frozen snapshot of it and a description
+// of the sources it was built from
+func loadWordBank(r *wordbank.Reader, p *parser.Parser, sources []wordbank.Source, lang string, timeout time.Duration) (*wordbank.Snapshot, models.WordBankInfo, error) {
+	// Initialize word bank with progress bar
+	wordBankCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	fmt.Printf("Initializing word bank (%s)...\n", lang)
+	bar := progressbar.NewOptions(-1,
+		progressbar.OptionSetDescription("Loading word bank..."),
+		progressbar.OptionSetWidth(30),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "=",
+			SaucerHead:    ">",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}))
+
+	info := models.WordBankInfo{Language: lang}
+	wb := wordbank.New()
+	wb.SetNormalizer(p.Normalize)
+	for _, source := range sources {
+		sourceBank := wordbank.New()
+		sourceBank.SetNormalizer(p.Normalize)
+		files, err := initializeWordBank(wordBankCtx, r, p, sourceBank, source, bar)
+		if err != nil {
+			return nil, info, err
+		}
+		if err := wb.Combine(source.Op, sourceBank); err != nil {
+			return nil, info, err
+		}
+		info.Sources = append(info.Sources, files...)
+	}
+	bar.Finish()
+
+	snapshot := wb.Freeze()
+	info.Words = snapshot.Len()
+	return snapshot, info, nil
+}
+
+// loadStopWords reads a stop list with one word per line. An empty path
+// yields an empty list.
+func loadStopWords(p *parser.Parser, path string) (map[string]struct{}, error) {
+	stopWords := make(map[string]struct{})
+	if path == "" {
+		return stopWords, nil
+	}
+
+	if err := addWordFile(p, stopWords, path); err != nil {
+		return nil, fmt.Errorf("error loading stop words file: %w", err)
+	}
+	return stopWords, nil
+}
+
+// loadWordFiles reads the words of each file, one per line, into a set
+func loadWordFiles(p *parser.Parser, paths []string) (map[string]struct{}, error) {
+	words := make(map[string]struct{})
+	for _, path := range paths {
+		if err := addWordFile(p, words, path); err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+// addWordFile adds the normalized words of a file, one per line, to words
+func addWordFile(p *parser.Parser, words map[string]struct{}, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	parsed, err := p.ParseWordBank(content)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	for _, word := range parsed {
+		words[word] = struct{}{}
+	}
+	return nil
+}
+
+// WordBankInfo describes the loaded word banks, ordered by language
+func (a *App) WordBankInfo() []models.WordBankInfo {
+	infos := make([]models.WordBankInfo, 0, len(a.languages))
+	for _, l := range a.languages {
+		infos = append(infos, l.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Language < infos[j].Language
+	})
+	return infos
+}
+
+// WordBanks returns the word bank of each language
+func (a *App) WordBanks() map[string]*wordbank.Snapshot {
+	banks := make(map[string]*wordbank.Snapshot, len(a.languages))
+	for lang, l := range a.languages {
+		banks[lang] = l.wordBank
+	}
+	return banks
+}
+
+// NormalizeWord normalizes and cleans word the way word bank entries are, so
+// that it can be looked up in a word bank
+func (a *App) NormalizeWord(word string) string {
+	return a.parser.CleanWord(word)
+}
+
+// detectLanguage returns the detected language of words, falling back to the
+// default language when detection is disabled or inconclusive
+func (a *App) detectLanguage(words []string) string {
+	if a.detector == nil {
+		return defaultLanguage(a.config)
+	}
+
+	if len(words) > maxDetectionWords {
+		words = words[:maxDetectionWords]
+	}
+	lang, _ := a.detector.Detect(words)
+	if lang == "" {
+		return defaultLanguage(a.config)
+	}
+	return lang
+}
+
+// bankLanguage returns the language whose word bank and stop list lang is
+// counted against. Languages without a configured word bank use the default.
+func (a *App) bankLanguage(lang string) string {
+	if _, ok := a.languages[lang]; ok {
+		return lang
+	}
+	return defaultLanguage(a.config)
+}
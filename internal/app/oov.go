@@ -0,0 +1,44 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+)
+
+// oovTopCount returns how many out-of-vocabulary words to report
+func oovTopCount(cfg *config.Config) int {
+	if cfg.OOV.TopCount <= 0 {
+		return 10
+	}
+	return cfg.OOV.TopCount
+}
+
+// writeOOVPatch writes the out-of-vocabulary words seen at least minCount
+// times to path, most frequent first and one per line, so that the file can
+// be reviewed and then added to the word bank as a union source
+func writeOOVPatch(path string, oov map[string]int, minCount int) error {
+	words := getTopWords(oov, len(oov))
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create OOV patch file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	for _, wc := range words {
+		if wc.Count < minCount {
+			break
+		}
+		fmt.Fprintln(w, wc.Word)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write OOV patch file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write OOV patch file: %w", err)
+	}
+	return nil
+}
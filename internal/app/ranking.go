@@ -0,0 +1,67 @@
+package app
+
+import (
+	"math"
+	"sort"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+)
+
+// Rankings of the top words
+const (
+	// rankByCount ranks words by how often they were counted
+	rankByCount = "count"
+	// rankByDocumentFrequency ranks words by how many articles they occur in
+	rankByDocumentFrequency = "documentFrequency"
+	// rankByBlend ranks words by count^(1-w) * documentFrequency^w for the
+	// configured blend weight w, so that a word repeated in a single article
+	// doesn't outrank one spread over many
+	rankByBlend = "blend"
+)
+
+// topWords returns the n top words ranked as configured, with their document
+// frequencies. Ties go to the higher count, then to the first alphabetically.
+func (a *App) topWords(counts *counter, n int) []models.WordCount {
+	words := make([]models.WordCount, 0, len(counts.total))
+	for word, count := range counts.total {
+		words = append(words, models.WordCount{
+			Word:              word,
+			Count:             count,
+			DocumentFrequency: counts.docs[word],
+		})
+	}
+
+	score := a.rankingScore()
+	sort.Slice(words, func(i, j int) bool {
+		// Blended scores are rounded, so nearly equal ones count as a tie
+		if si, sj := score(words[i]), score(words[j]); math.Abs(si-sj) > 1e-9*max(si, sj) {
+			return si > sj
+		}
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+
+	if len(words) > n {
+		return words[:n]
+	}
+	return words
+}
+
+// rankingScore returns the function scoring words for the configured ranking
+func (a *App) rankingScore() func(models.WordCount) float64 {
+	switch a.config.Output.Ranking {
+	case rankByDocumentFrequency:
+		return func(wc models.WordCount) float64 { return float64(wc.DocumentFrequency) }
+	case rankByBlend:
+		weight := 0.5
+		if a.config.Output.BlendWeight != nil {
+			weight = *a.config.Output.BlendWeight
+		}
+		return func(wc models.WordCount) float64 {
+			return math.Pow(float64(wc.Count), 1-weight) * math.Pow(float64(wc.DocumentFrequency), weight)
+		}
+	}
+	return func(wc models.WordCount) float64 { return float64(wc.Count) }
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/fetcher"
+)
+
+// recordWriter writes per-article records as NDJSON, one line per article
+// as it completes. It is safe for concurrent use.
+type recordWriter struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+	// err is the first write error, reported on close
+	err error
+}
+
+// createRecordWriter creates the per-article records file at path
+func createRecordWriter(path string) (*recordWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create article records file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	return &recordWriter{file: file, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+// write appends record to the file
+func (r *recordWriter) write(record models.ArticleRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(record)
+	}
+}
+
+// close flushes the records and closes the file
+func (r *recordWriter) close() error {
+	err := r.err
+	if err == nil {
+		err = r.w.Flush()
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write article records: %w", err)
+	}
+	return nil
+}
+
+// newArticleRecord describes the article at url from its response, if it
+// was fetched, the error it failed or was skipped with, if any, and the
+// counts of its words, of which the n most frequent are listed
+func newArticleRecord(url string, resp *fetcher.Response, err error, terms map[string]int, n int) models.ArticleRecord {
+	record := models.ArticleRecord{
+		URL:      url,
+		TopWords: getTopWords(terms, n),
+	}
+	if resp != nil {
+		record.FinalURL = resp.FinalURL
+		record.Status = resp.StatusCode
+		record.Bytes = resp.Bytes
+	}
+	if err != nil {
+		record.Error = err.Error()
+		var statusErr *fetcher.StatusError
+		if errors.As(err, &statusErr) {
+			record.Status = statusErr.StatusCode
+		}
+	}
+	if record.TopWords == nil {
+		record.TopWords = []models.WordCount{}
+	}
+	return record
+}
+
+// articleRecordTopCount returns how many top words to list per article record
+func articleRecordTopCount(cfg *config.Config) int {
+	if cfg.ArticleRecords.TopCount <= 0 {
+		return 10
+	}
+	return cfg.ArticleRecords.TopCount
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"maps"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+)
+
+// enabledRegions returns the document regions to extract, or nil to use the
+// parser's defaults when no regions are configured. Regions left out of the
+// configuration or without an enabled setting keep their default.
+func enabledRegions(cfg *config.Config) map[parser.Region]bool {
+	if len(cfg.Regions) == 0 {
+		return nil
+	}
+
+	regions := maps.Clone(parser.DefaultRegions)
+	for name, region := range cfg.Regions {
+		if region.Enabled != nil {
+			regions[parser.Region(name)] = *region.Enabled
+		}
+	}
+	return regions
+}
+
+// regionWeights returns the configured count multiplier of each region
+func regionWeights(cfg *config.Config) map[parser.Region]int {
+	weights := make(map[parser.Region]int, len(cfg.Regions))
+	for name, region := range cfg.Regions {
+		if region.Weight > 0 {
+			weights[parser.Region(name)] = region.Weight
+		}
+	}
+	return weights
+}
+
+// regionWeight returns how much a word found in region counts, 1 by default
+func (a *App) regionWeight(region parser.Region) int {
+	if weight, ok := a.weights[region]; ok {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NivBraz/wordcount-service/internal/config"
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+)
+
+func TestEnabledRegions(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name     string
+		regions  map[string]config.RegionConfig
+		expected map[parser.Region]bool
+	}{
+		{"none configured", nil, nil},
+		{
+			name:    "unlisted regions keep their default",
+			regions: map[string]config.RegionConfig{"navigation": {Enabled: &off}, "alt": {Enabled: &on}},
+			expected: map[parser.Region]bool{
+				parser.RegionTitle:      true,
+				parser.RegionHeadings:   true,
+				parser.RegionBody:       true,
+				parser.RegionCaptions:   true,
+				parser.RegionNavigation: false,
+				parser.RegionAltText:    true,
+			},
+		},
+		{
+			name:     "weight only",
+			regions:  map[string]config.RegionConfig{"title": {Weight: 2}},
+			expected: parser.DefaultRegions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Regions: tt.regions}
+			if got := enabledRegions(cfg); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("enabledRegions() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"io"
+)
+
+// URLSource supplies the article URLs to process
+type URLSource interface {
+	// Next returns the next URL, or io.EOF when there are no more
+	Next() (string, error)
+}
+
+// sliceSource supplies URLs from a list loaded up front
+type sliceSource struct {
+	urls []string
+	next int
+}
+
+func (s *sliceSource) Next() (string, error) {
+	if s.next == len(s.urls) {
+		return "", io.EOF
+	}
+	url := s.urls[s.next]
+	s.next++
+	return url, nil
+}
+
+// feed sends the URLs of src to urls, blocking while the queue is full. It
+// stops early when ctx is done, and returns any error reading src.
+func feed(ctx context.Context, src URLSource, urls chan<- string) error {
+	for {
+		url, err := src.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case urls <- url:
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/fetcher"
+)
+
+// runStats gathers the statistics of the articles a worker processed. Like
+// counter, each worker has its own, merged once the workers are done.
+type runStats struct {
+	articles models.ArticleStats
+	bytes    int64
+	latency  latencyHistogram
+}
+
+// add records an article processed with the response it was fetched with, if
+// any, and the error it failed or was skipped with, if any
+func (s *runStats) add(resp *fetcher.Response, err error) {
+	s.articles.Attempted++
+	var skip *skipError
+	switch {
+	case errors.As(err, &skip):
+		s.articles.Skipped++
+	case err != nil:
+		s.articles.Failed++
+	default:
+		s.articles.Succeeded++
+	}
+	if resp != nil {
+		s.bytes += resp.Bytes
+		s.latency.add(resp.Latency)
+	}
+}
+
+// merge adds the statistics of other to s
+func (s *runStats) merge(other *runStats) {
+	s.articles.Attempted += other.articles.Attempted
+	s.articles.Succeeded += other.articles.Succeeded
+	s.articles.Failed += other.articles.Failed
+	s.articles.Skipped += other.articles.Skipped
+	s.bytes += other.bytes
+	s.latency.merge(&other.latency)
+}
+
+// addTokenStats adds the token counts of other to s
+func addTokenStats(s *models.TokenStats, other models.TokenStats) {
+	s.Read += other.Read
+	s.Valid += other.Valid
+	s.NotDenied += other.NotDenied
+	s.NotStopWords += other.NotStopWords
+	s.InWordBank += other.InWordBank
+	s.Corrected += other.Corrected
+	s.Counted += other.Counted
+}
+
+// latencyGrowth is the ratio between the bounds of consecutive latency
+// buckets, so that percentiles are overestimated by at most 5%
+const latencyGrowth = 1.05
+
+// latencyHistogram counts latencies in buckets growing exponentially from
+// one microsecond to over an hour, so that percentiles can be estimated
+// without keeping a latency per article
+type latencyHistogram struct {
+	buckets [460]int
+	count   int
+	max     time.Duration
+}
+
+func (h *latencyHistogram) add(d time.Duration) {
+	i := 0
+	if us := float64(d.Microseconds()); us > 1 {
+		i = min(int(math.Ceil(math.Log(us)/math.Log(latencyGrowth))), len(h.buckets)-1)
+	}
+	h.buckets[i]++
+	h.count++
+	h.max = max(h.max, d)
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for i, n := range other.buckets {
+		h.buckets[i] += n
+	}
+	h.count += other.count
+	h.max = max(h.max, other.max)
+}
+
+// percentile returns the upper bound of the bucket holding the latency below
+// which a fraction p of the latencies fall, capped at the maximum latency
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(h.count)))
+	seen := 0
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank && n > 0 {
+			bound := time.Duration(math.Pow(latencyGrowth, float64(i))) * time.Microsecond
+			return min(bound, h.max)
+		}
+	}
+	return h.max
+}
+
+// stats summarizes a run from its merged statistics and counts
+func (s *runStats) stats(counts *counter, retries int, elapsed time.Duration) *models.Stats {
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	result := &models.Stats{
+		Articles:        s.articles,
+		Tokens:          counts.tokens,
+		DistinctWords:   len(counts.total),
+		BytesDownloaded: s.bytes,
+		Retries:         retries,
+		FetchLatency: models.LatencyStats{
+			P50: ms(s.latency.percentile(0.5)),
+			P90: ms(s.latency.percentile(0.9)),
+			P99: ms(s.latency.percentile(0.99)),
+			Max: ms(s.latency.max),
+		},
+		TimeElapsed: int(elapsed.Milliseconds()),
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		result.ArticlesPerSecond = float64(s.articles.Attempted) / seconds
+		result.BytesPerSecond = float64(s.bytes) / seconds
+	}
+	return result
+}
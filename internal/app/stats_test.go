@@ -0,0 +1,44 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var a, b latencyHistogram
+	for i := 1; i <= 100; i++ {
+		h := &a
+		if i%2 == 0 {
+			h = &b
+		}
+		h.add(time.Duration(i) * time.Millisecond)
+	}
+	a.merge(&b)
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0.5, 50 * time.Millisecond},
+		{0.9, 90 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		// Percentiles are the upper bounds of their buckets, at most 5% above
+		got := a.percentile(tt.p)
+		if got < tt.expected || float64(got) > float64(tt.expected)*latencyGrowth {
+			t.Errorf("percentile(%v) = %v, want %v to %v", tt.p, got, tt.expected, time.Duration(float64(tt.expected)*latencyGrowth))
+		}
+	}
+	if a.max != 100*time.Millisecond {
+		t.Errorf("Expected max 100ms, got %v", a.max)
+	}
+
+	var empty latencyHistogram
+	if got := empty.percentile(0.5); got != 0 {
+		t.Errorf("Expected 0 for no latencies, got %v", got)
+	}
+}
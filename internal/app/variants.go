@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NivBraz/wordcount-service/pkg/parser"
+)
+
+// loadVariants reads variant mapping files, each line holding a variant and
+// its canonical term separated by a comma or a tab, such as "colour,color".
+// Both are normalized like article words, and lines starting with # are
+// comments. The result maps variants to canonical terms, which must not be
+// variants themselves.
+func loadVariants(p *parser.Parser, paths []string) (map[string]string, error) {
+	variants := make(map[string]string)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		for n, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == '\t' })
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s line %d: expected a variant and its canonical term", path, n+1)
+			}
+			variant, canonical := p.CleanWord(fields[0]), p.CleanWord(fields[1])
+			if variant == "" || canonical == "" {
+				return nil, fmt.Errorf("%s line %d: empty variant or canonical term", path, n+1)
+			}
+			if variant == canonical {
+				// Cleaning alone already merges them, as with "e-mail" and "email"
+				continue
+			}
+			if previous, ok := variants[variant]; ok && previous != canonical {
+				return nil, fmt.Errorf("%s line %d: %q is already a variant of %q", path, n+1, variant, previous)
+			}
+			variants[variant] = canonical
+		}
+	}
+
+	for variant, canonical := range variants {
+		if _, ok := variants[canonical]; ok {
+			return nil, fmt.Errorf("canonical term %q of %q is itself a variant", canonical, variant)
+		}
+	}
+	return variants, nil
+}
+
+// canonicalTerms returns the set of terms variants map to
+func canonicalTerms(variants map[string]string) map[string]struct{} {
+	terms := make(map[string]struct{}, len(variants))
+	for _, canonical := range variants {
+		terms[canonical] = struct{}{}
+	}
+	return terms
+}
@@ -2,10 +2,9 @@
 package config
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -31,22 +30,138 @@ type Config struct {
 	} `yaml:"httpClient"`
 
 	Output struct {
-		TopWordsCount int    `yaml:"topWordsCount"`
-		IncludeStats  bool   `yaml:"includeStats"`
-		Format        string `yaml:"format"`
-		PrettyPrint   bool   `yaml:"prettyPrint"`
+		TopWordsCount   int      `yaml:"topWordsCount"`
+		IncludeStats    bool     `yaml:"includeStats"`
+		IncludeArticles bool     `yaml:"includeArticles"`
+		SplitByRegion   bool     `yaml:"splitByRegion"`
+		ByCategory      bool     `yaml:"byCategory"`
+		ByPartOfSpeech  bool     `yaml:"byPartOfSpeech"`
+		CompareBaseline bool     `yaml:"compareBaseline"`
+		Ranking         string   `yaml:"ranking"`
+		BlendWeight     *float64 `yaml:"blendWeight"` // nil when unset, so that 0 can be set
+		Format          string   `yaml:"format"`
+		PrettyPrint     bool     `yaml:"prettyPrint"`
 	} `yaml:"output"`
 
 	WordProcessing struct {
-		MinWordLength      int  `yaml:"minWordLength"`
-		ConvertToLower     bool `yaml:"convertToLower"`
-		RemoveSpecialChars bool `yaml:"removeSpecialChars"`
+		MinWordLength      int    `yaml:"minWordLength"`
+		ConvertToLower     bool   `yaml:"convertToLower"`
+		RemoveSpecialChars bool   `yaml:"removeSpecialChars"`
+		Normalization      string `yaml:"normalization"`
+		FoldDiacritics     bool   `yaml:"foldDiacritics"`
 	} `yaml:"wordProcessing"`
 
+	WordBank struct {
+		Sources  []WordBankSource `yaml:"sources"`
+		Timeout  int              `yaml:"timeout"`
+		CacheDir string           `yaml:"cacheDir"`
+	} `yaml:"wordBank"`
+
+	Filters struct {
+		DenyFiles  []string `yaml:"denyFiles"`
+		AllowFiles []string `yaml:"allowFiles"`
+	} `yaml:"filters"`
+
+	Variants struct {
+		Files     []string `yaml:"files"`
+		Breakdown bool     `yaml:"breakdown"`
+	} `yaml:"variants"`
+
+	OOV struct {
+		Track     bool   `yaml:"track"`
+		TopCount  int    `yaml:"topCount"`
+		MinCount  int    `yaml:"minCount"`
+		PatchFile string `yaml:"patchFile"`
+	} `yaml:"oov"`
+
+	Export struct {
+		File   string `yaml:"file"`
+		Format string `yaml:"format"`
+	} `yaml:"export"`
+
+	ArticleRecords struct {
+		File     string `yaml:"file"`
+		TopCount int    `yaml:"topCount"`
+	} `yaml:"articleRecords"`
+
+	Keywords struct {
+		Enabled  bool   `yaml:"enabled"`
+		TopCount int    `yaml:"topCount"`
+		LoadIDF  string `yaml:"loadIDF"`
+		SaveIDF  string `yaml:"saveIDF"`
+	} `yaml:"keywords"`
+
+	Fuzzy struct {
+		Enabled       bool `yaml:"enabled"`
+		MaxDistance   int  `yaml:"maxDistance"`
+		MinWordLength int  `yaml:"minWordLength"`
+	} `yaml:"fuzzy"`
+
+	Extraction struct {
+		Strategy string            `yaml:"strategy"`
+		Domains  map[string]string `yaml:"domains"`
+	} `yaml:"extraction"`
+
+	Regions map[string]RegionConfig `yaml:"regions"`
+
+	Languages struct {
+		Detect  bool                      `yaml:"detect"`
+		Default string                    `yaml:"default"`
+		Banks   map[string]LanguageConfig `yaml:"banks"`
+	} `yaml:"languages"`
+
+	Queue struct {
+		Size       int  `yaml:"size"`
+		StreamURLs bool `yaml:"streamURLs"`
+	} `yaml:"queue"`
+
+	Streaming struct {
+		Enabled      bool  `yaml:"enabled"`
+		MaxBodyBytes int64 `yaml:"maxBodyBytes"`
+	} `yaml:"streaming"`
+
 	// This will be populated from the file
 	ArticleURLs []string `yaml:"-"`
 }
 
+// RegionConfig controls whether words from a document region are counted
+// and how much each occurrence weighs. A region whose Enabled is unset keeps
+// its default.
+type RegionConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	Weight  int   `yaml:"weight"`
+}
+
+// validRegions are the document regions words can be extracted from
+var validRegions = map[string]bool{
+	"title":      true,
+	"headings":   true,
+	"body":       true,
+	"captions":   true,
+	"navigation": true,
+	"alt":        true,
+	"meta":       true,
+}
+
+// LanguageConfig holds the word bank and stop list used for one language
+type LanguageConfig struct {
+	WordBankURL     string           `yaml:"wordBankURL"`
+	WordBankSources []WordBankSource `yaml:"wordBankSources"`
+	StopWordsFile   string           `yaml:"stopWordsFile"`
+}
+
+// WordBankSource is a word list, local or remote and optionally gzipped, and
+// how it is combined with the sources listed before it. Format is list, tsv,
+// csv or hunspell, detected from the file extension when empty. A Hunspell
+// .dic dictionary is expanded with the rules of Affix, which defaults to the
+// .aff file next to it.
+type WordBankSource struct {
+	Source string `yaml:"source"`
+	Op     string `yaml:"op"`
+	Format string `yaml:"format"`
+	Affix  string `yaml:"affix"`
+}
+
 // Load reads and parses the configuration
 func Load() (*Config, error) {
 	// Load YAML config
@@ -62,12 +177,14 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error decoding config: %w", err)
 	}
 
-	// Load URLs from file
-	urls, err := loadURLsFromFile(cfg.URLs.ArticleURLsFile)
-	if err != nil {
-		return nil, fmt.Errorf("error loading URLs from file: %w", err)
+	// Load URLs from file, unless they are read while processing
+	if !cfg.Queue.StreamURLs {
+		urls, err := loadURLsFromFile(cfg.URLs.ArticleURLsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading URLs from file: %w", err)
+		}
+		cfg.ArticleURLs = urls
 	}
-	cfg.ArticleURLs = urls
 
 	// Set default values
 	setDefaults(&cfg)
@@ -82,27 +199,22 @@ func Load() (*Config, error) {
 
 // loadURLsFromFile reads URLs from the specified file
 func loadURLsFromFile(filepath string) ([]string, error) {
-	file, err := os.Open(filepath)
+	r, err := OpenURLs(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("error opening URLs file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
+	defer r.Close()
 
 	var urls []string
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		// Get the line and trim spaces
-		url := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines and comments
-		if url != "" && !strings.HasPrefix(url, "#") {
-			urls = append(urls, url)
+	for {
+		url, err := r.Next()
+		if err == io.EOF {
+			break
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading URLs file: %w", err)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
 	}
 
 	if len(urls) == 0 {
@@ -132,14 +244,30 @@ func setDefaults(cfg *Config) {
 	if cfg.Output.TopWordsCount == 0 {
 		cfg.Output.TopWordsCount = 10
 	}
+	if cfg.Languages.Default == "" {
+		cfg.Languages.Default = "en"
+	}
+	if cfg.WordBank.Timeout == 0 {
+		cfg.WordBank.Timeout = 30
+	}
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.URLs.WordBankURL == "" {
+	if c.URLs.WordBankURL == "" && len(c.WordBank.Sources) == 0 {
 		return fmt.Errorf("wordBankURL is required")
 	}
-	if len(c.ArticleURLs) == 0 {
+	if err := validateSources(c.WordBank.Sources); err != nil {
+		return err
+	}
+	if c.WordBank.Timeout < 0 {
+		return fmt.Errorf("word bank timeout must not be negative")
+	}
+	if c.Queue.StreamURLs {
+		if c.URLs.ArticleURLsFile == "" {
+			return fmt.Errorf("articleURLsFile is required to stream URLs")
+		}
+	} else if len(c.ArticleURLs) == 0 {
 		return fmt.Errorf("no article URLs loaded from file")
 	}
 	if c.RateLimit.RequestsPerSecond <= 0 {
@@ -148,5 +276,102 @@ func (c *Config) Validate() error {
 	if c.Concurrency <= 0 {
 		return fmt.Errorf("concurrency must be positive")
 	}
+	switch c.WordProcessing.Normalization {
+	case "", "nfc", "nfkc":
+	default:
+		return fmt.Errorf("unsupported normalization %q: must be nfc or nfkc", c.WordProcessing.Normalization)
+	}
+	if err := validateStrategy(c.Extraction.Strategy); err != nil {
+		return err
+	}
+	for domain, strategy := range c.Extraction.Domains {
+		if err := validateStrategy(strategy); err != nil {
+			return fmt.Errorf("domain %s: %w", domain, err)
+		}
+	}
+	for name, region := range c.Regions {
+		if !validRegions[name] {
+			return fmt.Errorf("unknown region %q", name)
+		}
+		if region.Weight < 0 {
+			return fmt.Errorf("weight of region %q must not be negative", name)
+		}
+	}
+	if c.OOV.TopCount < 0 || c.OOV.MinCount < 0 {
+		return fmt.Errorf("oov topCount and minCount must not be negative")
+	}
+	switch c.Output.Ranking {
+	case "", "count", "documentFrequency", "blend":
+	default:
+		return fmt.Errorf("unsupported ranking %q: must be count, documentFrequency or blend", c.Output.Ranking)
+	}
+	if w := c.Output.BlendWeight; w != nil && (*w < 0 || *w > 1) {
+		return fmt.Errorf("blendWeight must be between 0 and 1")
+	}
+	switch c.Export.Format {
+	case "", "csv", "json", "ndjson":
+	default:
+		return fmt.Errorf("unsupported export format %q: must be csv, json or ndjson", c.Export.Format)
+	}
+	if c.ArticleRecords.TopCount < 0 {
+		return fmt.Errorf("articleRecords topCount must not be negative")
+	}
+	if c.Keywords.TopCount < 0 {
+		return fmt.Errorf("keywords topCount must not be negative")
+	}
+	if c.Fuzzy.MaxDistance < 0 || c.Fuzzy.MaxDistance > 2 {
+		return fmt.Errorf("fuzzy maxDistance must be between 0 and 2")
+	}
+	if c.Fuzzy.MinWordLength < 0 {
+		return fmt.Errorf("fuzzy minWordLength must not be negative")
+	}
+	if c.Queue.Size < 0 {
+		return fmt.Errorf("queue size must not be negative")
+	}
+	if c.Streaming.MaxBodyBytes < 0 {
+		return fmt.Errorf("maxBodyBytes must not be negative")
+	}
+	for lang, bank := range c.Languages.Banks {
+		if bank.WordBankURL == "" && len(bank.WordBankSources) == 0 && lang != c.Languages.Default {
+			return fmt.Errorf("wordBankURL is required for language %q", lang)
+		}
+		if err := validateSources(bank.WordBankSources); err != nil {
+			return fmt.Errorf("language %s: %w", lang, err)
+		}
+	}
 	return nil
 }
+
+// validateSources checks that word bank sources are set and combined with
+// supported operators, the first one adding to an empty word bank
+func validateSources(sources []WordBankSource) error {
+	for i, source := range sources {
+		if source.Source == "" {
+			return fmt.Errorf("word bank source %d has no location", i+1)
+		}
+		switch source.Op {
+		case "", "union":
+		case "intersect", "subtract":
+			if i == 0 {
+				return fmt.Errorf("first word bank source can't use %q", source.Op)
+			}
+		default:
+			return fmt.Errorf("unsupported word bank operator %q: must be union, intersect or subtract", source.Op)
+		}
+		switch source.Format {
+		case "", "list", "tsv", "csv", "hunspell":
+		default:
+			return fmt.Errorf("unsupported word bank format %q: must be list, tsv, csv or hunspell", source.Format)
+		}
+	}
+	return nil
+}
+
+// validateStrategy checks that strategy names a supported extraction strategy
+func validateStrategy(strategy string) error {
+	switch strategy {
+	case "", "dom", "jsonld":
+		return nil
+	}
+	return fmt.Errorf("unsupported extraction strategy %q: must be dom or jsonld", strategy)
+}
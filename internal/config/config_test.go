@@ -1,7 +1,10 @@
 package config
 
 import (
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -169,3 +172,115 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls")
+	content := "# comment\nhttps://example.com/a\n\n  https://example.com/b  \r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create URLs file: %v", err)
+	}
+
+	r, err := OpenURLs(path)
+	if err != nil {
+		t.Fatalf("OpenURLs() error = %v", err)
+	}
+	defer r.Close()
+
+	var urls []string
+	for {
+		url, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		urls = append(urls, url)
+	}
+
+	if expected := []string{"https://example.com/a", "https://example.com/b"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected URLs %v, got %v", expected, urls)
+	}
+}
+
+func TestConfig_ValidateStreamURLs(t *testing.T) {
+	cfg := &Config{Concurrency: 4}
+	cfg.RateLimit.RequestsPerSecond = 4
+	cfg.URLs.WordBankURL = "https://example.com/words.txt"
+	cfg.Queue.StreamURLs = true
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error without an articleURLsFile")
+	}
+
+	cfg.URLs.ArticleURLsFile = "endg-urls"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil without preloaded URLs", err)
+	}
+}
+
+func TestConfig_ValidateWordBankSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []WordBankSource
+		wantErr bool
+	}{
+		{"local and remote", []WordBankSource{{Source: "words.txt.gz"}, {Source: "https://example.com/extra.txt", Op: "union"}}, false},
+		{"subtract", []WordBankSource{{Source: "words.txt"}, {Source: "excluded.txt", Op: "subtract"}}, false},
+		{"first source intersects", []WordBankSource{{Source: "words.txt", Op: "intersect"}}, true},
+		{"unknown operator", []WordBankSource{{Source: "words.txt"}, {Source: "more.txt", Op: "xor"}}, true},
+		{"missing location", []WordBankSource{{Op: "union"}}, true},
+		{"table formats", []WordBankSource{{Source: "words.tsv"}, {Source: "https://example.com/words", Format: "csv"}}, false},
+		{"unknown format", []WordBankSource{{Source: "words.xlsx", Format: "xlsx"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Concurrency: 4, ArticleURLs: []string{"https://example.com/article"}}
+			cfg.RateLimit.RequestsPerSecond = 4
+			cfg.WordBank.Sources = tt.sources
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateRanking(t *testing.T) {
+	tests := []struct {
+		name        string
+		ranking     string
+		blendWeight *float64
+		wantErr     bool
+	}{
+		{"default", "", nil, false},
+		{"document frequency", "documentFrequency", nil, false},
+		{"blend", "blend", weight(0.25), false},
+		{"blend weight of 0", "blend", weight(0), false},
+		{"unknown ranking", "tfidf", nil, true},
+		{"blend weight out of range", "blend", weight(1.5), true},
+		{"negative blend weight", "blend", weight(-0.5), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Concurrency: 4, ArticleURLs: []string{"https://example.com/article"}}
+			cfg.RateLimit.RequestsPerSecond = 4
+			cfg.URLs.WordBankURL = "https://example.com/words.txt"
+			cfg.Output.Ranking = tt.ranking
+			cfg.Output.BlendWeight = tt.blendWeight
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// weight returns a pointer to a blend weight
+func weight(w float64) *float64 {
+	return &w
+}
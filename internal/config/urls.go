@@ -0,0 +1,49 @@
+// internal/config/urls.go
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// URLReader reads article URLs from a file one at a time, skipping empty
+// lines and comments
+type URLReader struct {
+	file    *os.File
+	scanner *bufio.Scanner
+}
+
+// OpenURLs opens a file of article URLs, one per line
+func OpenURLs(path string) (*URLReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening URLs file: %w", err)
+	}
+	return &URLReader{file: file, scanner: bufio.NewScanner(file)}, nil
+}
+
+// Next returns the next URL, or io.EOF after the last one
+func (r *URLReader) Next() (string, error) {
+	for r.scanner.Scan() {
+		// Get the line and trim spaces
+		url := strings.TrimSpace(r.scanner.Text())
+
+		// Skip empty lines and comments
+		if url != "" && !strings.HasPrefix(url, "#") {
+			return url, nil
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading URLs file: %w", err)
+	}
+	return "", io.EOF
+}
+
+// Close closes the underlying file
+func (r *URLReader) Close() error {
+	return r.file.Close()
+}
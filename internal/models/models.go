@@ -3,12 +3,163 @@ package models
 type WordCount struct {
 	Word  string `json:"word"`
 	Count int    `json:"count"`
+	// DocumentFrequency is the number of articles the word occurs in
+	DocumentFrequency int `json:"documentFrequency,omitempty"`
+}
+
+type ArticleMetadata struct {
+	Title        string   `json:"title,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	PublishedAt  string   `json:"publishedAt,omitempty"`
+	CanonicalURL string   `json:"canonicalUrl,omitempty"`
+	Section      string   `json:"section,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+type ArticleResult struct {
+	URL         string          `json:"url"`
+	ContentType string          `json:"contentType"`
+	Encoding    string          `json:"encoding"`
+	Strategy    string          `json:"strategy,omitempty"`
+	Language    string          `json:"language,omitempty"`
+	Truncated   bool            `json:"truncated,omitempty"`
+	Metadata    ArticleMetadata `json:"metadata"`
+}
+
+// ArticleRecord is what an article contributed to a run, written as one line
+// of the per-article NDJSON output
+type ArticleRecord struct {
+	URL      string `json:"url"`
+	FinalURL string `json:"finalUrl,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Bytes    int64  `json:"bytes"`
+	// Tokens is the number of words read, and MatchedTokens how many of them
+	// were counted
+	Tokens        int         `json:"tokens"`
+	MatchedTokens int         `json:"matchedTokens"`
+	TopWords      []WordCount `json:"topWords"`
+	DurationMs    int64       `json:"durationMs"`
+	Error         string      `json:"error,omitempty"`
+}
+
+type SkippedArticle struct {
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Reason      string `json:"reason"`
+}
+
+// WordBankSource describes one word list of a word bank as it was loaded
+type WordBankSource struct {
+	Location string `json:"location"`
+	SHA256   string `json:"sha256"`
+	Version  string `json:"version,omitempty"`
+	Origin   string `json:"origin"`
+	Warning  string `json:"warning,omitempty"`
+}
+
+// WordBankInfo identifies the word bank a language was counted against
+type WordBankInfo struct {
+	Language string           `json:"language"`
+	Words    int              `json:"words"`
+	Sources  []WordBankSource `json:"sources"`
+}
+
+// BaselineComparison compares how often a word was counted with how often
+// its word bank frequency says it should have been
+type BaselineComparison struct {
+	Word     string  `json:"word"`
+	Count    int     `json:"count"`
+	Expected float64 `json:"expected"`
+	// Ratio is Count over Expected: above 1 the word is over-represented
+	Ratio float64 `json:"ratio"`
+}
+
+// Correction is a misspelled word counted as the closest word bank word
+type Correction struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+// Keyword is a word scored by TF-IDF, its frequency in an article weighted by
+// how rare it is across articles
+type Keyword struct {
+	Word  string  `json:"word"`
+	Score float64 `json:"score"`
+}
+
+// ArticleKeywords lists the keywords of an article, best first
+type ArticleKeywords struct {
+	URL      string    `json:"url"`
+	Keywords []Keyword `json:"keywords"`
+}
+
+// Stats describes a run: what became of its articles and of their tokens,
+// how much was downloaded and how fast
+type Stats struct {
+	Articles        ArticleStats `json:"articles"`
+	Tokens          TokenStats   `json:"tokens"`
+	DistinctWords   int          `json:"distinctWords"`
+	BytesDownloaded int64        `json:"bytesDownloaded"`
+	Retries         int          `json:"retries"`
+	// FetchLatency is measured over the successful fetches
+	FetchLatency      LatencyStats `json:"fetchLatencyMs"`
+	ArticlesPerSecond float64      `json:"articlesPerSecond"`
+	BytesPerSecond    float64      `json:"bytesPerSecond"`
+	TimeElapsed       int          `json:"timeElapsedMs"`
+}
+
+// ArticleStats counts the articles attempted by how they ended
+type ArticleStats struct {
+	Attempted int `json:"attempted"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// TokenStats counts the tokens read and how many of them passed each filter
+// in turn
+type TokenStats struct {
+	Read int `json:"read"`
+	// Valid tokens are alphabetic words of at least three letters
+	Valid        int `json:"valid"`
+	NotDenied    int `json:"notDenied"`
+	NotStopWords int `json:"notStopWords"`
+	InWordBank   int `json:"inWordBank"`
+	// Corrected tokens missing from the word bank were counted as the closest
+	// word bank word
+	Corrected int `json:"corrected"`
+	Counted   int `json:"counted"`
+}
+
+// LatencyStats summarizes a latency distribution in milliseconds
+type LatencyStats struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
 }
 
 type Result struct {
-	TopWords []WordCount `json:"topWords"`
-	Stats    struct {
-		TotalProcessed int `json:"totalProcessed"`
-		TimeElapsed    int `json:"timeElapsedMs"`
-	} `json:"stats"`
+	TopWords   []WordCount            `json:"topWords"`
+	Stats      *Stats                 `json:"stats,omitempty"`
+	ByLanguage map[string][]WordCount `json:"byLanguage,omitempty"`
+	ByRegion   map[string][]WordCount `json:"byRegion,omitempty"`
+	// ByCategory and ByPartOfSpeech group the words of tabular word banks
+	ByCategory     map[string][]WordCount `json:"byCategory,omitempty"`
+	ByPartOfSpeech map[string][]WordCount `json:"byPartOfSpeech,omitempty"`
+	Baseline       []BaselineComparison   `json:"baseline,omitempty"`
+	Denied         []WordCount            `json:"denied,omitempty"`
+	OOV            []WordCount            `json:"oov,omitempty"`
+	// Variants breaks the counts of canonical terms down by spelling
+	Variants map[string][]WordCount `json:"variants,omitempty"`
+	// Corrections lists the fuzzy matches applied, most frequent first
+	Corrections []Correction `json:"corrections,omitempty"`
+	// Keywords lists the keywords of each article, and CorpusKeywords the
+	// terms most distinctive of all articles
+	Keywords       []ArticleKeywords `json:"keywords,omitempty"`
+	CorpusKeywords []Keyword         `json:"corpusKeywords,omitempty"`
+	WordBanks      []WordBankInfo    `json:"wordBanks,omitempty"`
+	Articles       []ArticleResult   `json:"articles,omitempty"`
+	Skipped        []SkippedArticle  `json:"skipped,omitempty"`
 }
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
+)
+
+const (
+	// defaultLimit is how many words a query returns when no limit is given
+	defaultLimit = 100
+	// maxLimit is the most words a single query may return
+	maxLimit = 10000
+)
+
+// WordBanks is the read-only view of the loaded word banks the endpoints query
+type WordBanks interface {
+	WordBanks() map[string]*wordbank.Snapshot
+	WordBankInfo() []models.WordBankInfo
+	NormalizeWord(word string) string
+}
+
+// wordList is the response to queries returning words
+type wordList struct {
+	Language string   `json:"language"`
+	Words    []string `json:"words"`
+}
+
+// NewWordBankHandler serves read-only queries on the word banks:
+//
+//	GET /wordbank                      the word banks and their sizes and sources
+//	GET /wordbank/{lang}               a single word bank
+//	GET /wordbank/{lang}/contains?word=
+//	GET /wordbank/{lang}/prefix?q=&limit=
+//	GET /wordbank/{lang}/search?regex=&limit= or ?glob=&limit=
+//	GET /wordbank/{lang}/sample?n=
+func NewWordBankHandler(banks WordBanks) http.Handler {
+	h := &wordBankHandler{banks: banks}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wordbank", h.list)
+	mux.HandleFunc("GET /wordbank/{lang}", h.info)
+	mux.HandleFunc("GET /wordbank/{lang}/contains", h.contains)
+	mux.HandleFunc("GET /wordbank/{lang}/prefix", h.prefix)
+	mux.HandleFunc("GET /wordbank/{lang}/search", h.search)
+	mux.HandleFunc("GET /wordbank/{lang}/sample", h.sample)
+	return mux
+}
+
+type wordBankHandler struct {
+	banks WordBanks
+}
+
+func (h *wordBankHandler) list(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.banks.WordBankInfo())
+}
+
+func (h *wordBankHandler) info(w http.ResponseWriter, r *http.Request) {
+	lang := r.PathValue("lang")
+	for _, info := range h.banks.WordBankInfo() {
+		if info.Language == lang {
+			writeJSON(w, http.StatusOK, info)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("no word bank for language %q", lang))
+}
+
+func (h *wordBankHandler) contains(w http.ResponseWriter, r *http.Request) {
+	bank, ok := h.bank(w, r)
+	if !ok {
+		return
+	}
+	word := h.banks.NormalizeWord(r.URL.Query().Get("word"))
+	writeJSON(w, http.StatusOK, struct {
+		Word     string `json:"word"`
+		Contains bool   `json:"contains"`
+	}{word, word != "" && bank.Contains(word)})
+}
+
+func (h *wordBankHandler) prefix(w http.ResponseWriter, r *http.Request) {
+	bank, ok := h.bank(w, r)
+	if !ok {
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	prefix := h.banks.NormalizeWord(r.URL.Query().Get("q"))
+	h.writeWords(w, r, bank.Prefix(prefix, limit))
+}
+
+func (h *wordBankHandler) search(w http.ResponseWriter, r *http.Request) {
+	bank, ok := h.bank(w, r)
+	if !ok {
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	query := r.URL.Query()
+	var words []string
+	switch {
+	case query.Has("regex"):
+		re, err := regexp.Compile(query.Get("regex"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid regex: %w", err))
+			return
+		}
+		words = bank.Match(re, limit)
+	case query.Has("glob"):
+		words, err = bank.Glob(query.Get("glob"), limit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid glob: %w", err))
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("either regex or glob is required"))
+		return
+	}
+	h.writeWords(w, r, words)
+}
+
+func (h *wordBankHandler) sample(w http.ResponseWriter, r *http.Request) {
+	bank, ok := h.bank(w, r)
+	if !ok {
+		return
+	}
+	n, err := queryInt(r, "n", 10)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.writeWords(w, r, bank.Sample(n))
+}
+
+// bank returns the word bank of the requested language, writing a 404 if
+// there is none
+func (h *wordBankHandler) bank(w http.ResponseWriter, r *http.Request) (*wordbank.Snapshot, bool) {
+	lang := r.PathValue("lang")
+	bank, ok := h.banks.WordBanks()[lang]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no word bank for language %q", lang))
+	}
+	return bank, ok
+}
+
+func (h *wordBankHandler) writeWords(w http.ResponseWriter, r *http.Request, words []string) {
+	if words == nil {
+		words = []string{}
+	}
+	writeJSON(w, http.StatusOK, wordList{Language: r.PathValue("lang"), Words: words})
+}
+
+// queryInt returns the positive integer query parameter name, capped at
+// maxLimit, or def if it is missing
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive integer", name, value)
+	}
+	return min(n, maxLimit), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+	"github.com/NivBraz/wordcount-service/pkg/wordbank"
+)
+
+type testBanks map[string]*wordbank.Snapshot
+
+func (b testBanks) WordBanks() map[string]*wordbank.Snapshot { return b }
+
+func (b testBanks) WordBankInfo() []models.WordBankInfo {
+	return []models.WordBankInfo{{Language: "en", Words: b["en"].Len()}}
+}
+
+func (b testBanks) NormalizeWord(word string) string { return strings.ToLower(word) }
+
+func TestWordBankHandler(t *testing.T) {
+	wb := wordbank.New()
+	for _, word := range []string{"car", "card", "cart", "cat", "dog"} {
+		wb.Add(word)
+	}
+	handler := NewWordBankHandler(testBanks{"en": wb.Freeze()})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"list", "/wordbank", http.StatusOK, `[{"language":"en","words":5,"sources":null}]`},
+		{"info", "/wordbank/en", http.StatusOK, `{"language":"en","words":5,"sources":null}`},
+		{"unknown language", "/wordbank/fr/prefix?q=ca", http.StatusNotFound, `{"error":"no word bank for language \"fr\""}`},
+		{"contains", "/wordbank/en/contains?word=Cart", http.StatusOK, `{"word":"cart","contains":true}`},
+		{"missing", "/wordbank/en/contains?word=cow", http.StatusOK, `{"word":"cow","contains":false}`},
+		{"prefix", "/wordbank/en/prefix?q=CAR&limit=2", http.StatusOK, `{"language":"en","words":["car","card"]}`},
+		{"no match", "/wordbank/en/prefix?q=z", http.StatusOK, `{"language":"en","words":[]}`},
+		{"regex", "/wordbank/en/search?regex=t$", http.StatusOK, `{"language":"en","words":["cart","cat"]}`},
+		{"glob", "/wordbank/en/search?glob=ca?", http.StatusOK, `{"language":"en","words":["car","cat"]}`},
+		{"invalid regex", "/wordbank/en/search?regex=(", http.StatusBadRequest, ""},
+		{"no pattern", "/wordbank/en/search", http.StatusBadRequest, ""},
+		{"invalid limit", "/wordbank/en/prefix?q=c&limit=-1", http.StatusBadRequest, ""},
+		{"read only", "/wordbank/en", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tt.wantStatus == http.StatusMethodNotAllowed {
+				method = http.MethodPost
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("Expected body %s, got %s", tt.wantBody, rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wordbank/en/sample?n=3", nil))
+	var sample wordList
+	if err := json.NewDecoder(rec.Body).Decode(&sample); err != nil {
+		t.Fatalf("Failed to decode sample: %v", err)
+	}
+	if len(sample.Words) != 3 || sample.Words[0] == sample.Words[1] {
+		t.Errorf("Expected 3 distinct sampled words, got %v", sample.Words)
+	}
+}
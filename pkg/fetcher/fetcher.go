@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -25,6 +26,7 @@ type Fetcher struct {
 	mu              sync.Mutex
 	userAgents      []string
 	currentUAIndex  int
+	retries         atomic.Int64
 }
 
 type FetcherConfig struct {
@@ -37,6 +39,38 @@ type FetcherConfig struct {
 	MaxRetries         int
 	InitialBackoff     time.Duration
 	MaxBackoff         time.Duration
+	// MaxBodyBytes truncates response bodies longer than this, 0 for no limit
+	MaxBodyBytes int64
+}
+
+// Response holds the body of a successful fetch along with the response metadata
+// needed to interpret it
+type Response struct {
+	Body        []byte
+	ContentType string
+	FinalURL    string
+	StatusCode  int
+	// Bytes is the number of body bytes read
+	Bytes int64
+	// Latency is the time from sending the successful request to reading the
+	// body, which includes the time spent reading it when streaming
+	Latency time.Duration
+	// Truncated reports that the body was cut off at MaxBodyBytes
+	Truncated bool
+}
+
+// StatusError reports a fetch that failed on the status code of its last
+// response once retries were exhausted
+type StatusError struct {
+	StatusCode int
+	Attempts   int
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests || e.StatusCode == 999 {
+		return fmt.Sprintf("rate limit exceeded after %d retries", e.Attempts)
+	}
+	return fmt.Sprintf("unexpected status code %d after %d retries", e.StatusCode, e.Attempts)
 }
 
 var defaultUserAgents = []string{
@@ -79,6 +113,11 @@ func New(config FetcherConfig) *Fetcher {
 	}
 }
 
+// Retries returns the number of requests retried since the fetcher was created
+func (f *Fetcher) Retries() int64 {
+	return f.retries.Load()
+}
+
 func (f *Fetcher) rotateUserAgent() string {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -119,11 +158,40 @@ func (f *Fetcher) BasicFetch(ctx context.Context, urlStr string) ([]byte, error)
 	return body, nil
 }
 
+// Fetch retrieves the body of urlStr, retrying with backoff on failures
 func (f *Fetcher) Fetch(ctx context.Context, urlStr string) ([]byte, error) {
+	resp, err := f.FetchResponse(ctx, urlStr)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// FetchResponse is like Fetch but also returns the response metadata
+func (f *Fetcher) FetchResponse(ctx context.Context, urlStr string) (*Response, error) {
+	return f.fetch(ctx, urlStr, true, func(resp *Response, body io.Reader) error {
+		var err error
+		resp.Body, err = io.ReadAll(body)
+		return err
+	})
+}
+
+// FetchStream fetches urlStr like FetchResponse but hands the body to read as
+// it arrives instead of buffering it. Since read may already have consumed
+// part of the body, errors while reading are returned without retrying, along
+// with the response read so far.
+func (f *Fetcher) FetchStream(ctx context.Context, urlStr string, read func(*Response, io.Reader) error) (*Response, error) {
+	return f.fetch(ctx, urlStr, false, read)
+}
+
+// fetch runs the request loop shared by FetchResponse and FetchStream,
+// passing successful responses to read with the body limited to MaxBodyBytes
+func (f *Fetcher) fetch(ctx context.Context, urlStr string, retryReads bool, read func(*Response, io.Reader) error) (*Response, error) {
 	var lastErr error
 
 	for attempt := 0; attempt <= f.config.MaxRetries; attempt++ {
 		if attempt > 0 {
+			f.retries.Add(1)
 			backoff := f.calculateBackoff(attempt - 1)
 			select {
 			case <-ctx.Done():
@@ -222,6 +290,7 @@ func (f *Fetcher) Fetch(ctx context.Context, urlStr string) ([]byte, error) {
 		req.Header.Set("Sec-Fetch-User", "?1")
 		req.Header.Set("Cache-Control", "max-age=0")
 
+		start := time.Now()
 		resp, err := f.client.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("error fetching URL (attempt %d): %w", attempt+1, err)
@@ -231,18 +300,27 @@ func (f *Fetcher) Fetch(ctx context.Context, urlStr string) ([]byte, error) {
 		// Handle different status codes
 		switch resp.StatusCode {
 		case http.StatusOK:
-			body, err := io.ReadAll(resp.Body)
+			result := &Response{
+				ContentType: resp.Header.Get("Content-Type"),
+				FinalURL:    resp.Request.URL.String(),
+				StatusCode:  resp.StatusCode,
+			}
+			err := f.readBody(resp.Body, result, read)
 			resp.Body.Close()
+			result.Latency = time.Since(start)
 			if err != nil {
+				if !retryReads {
+					return result, fmt.Errorf("error reading response body: %w", err)
+				}
 				lastErr = fmt.Errorf("error reading response body: %w", err)
 				continue
 			}
-			return body, nil
+			return result, nil
 
 		case http.StatusTooManyRequests, 999: // Rate limit cases
 			resp.Body.Close()
 			if attempt == f.config.MaxRetries {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", attempt+1)
+				return nil, &StatusError{StatusCode: resp.StatusCode, Attempts: attempt + 1}
 			}
 			lastErr = fmt.Errorf("rate limit exceeded (status %d), retrying...", resp.StatusCode)
 			continue
@@ -250,7 +328,7 @@ func (f *Fetcher) Fetch(ctx context.Context, urlStr string) ([]byte, error) {
 		default:
 			resp.Body.Close()
 			if attempt == f.config.MaxRetries {
-				return nil, fmt.Errorf("unexpected status code %d after %d retries", resp.StatusCode, attempt+1)
+				return nil, &StatusError{StatusCode: resp.StatusCode, Attempts: attempt + 1}
 			}
 			lastErr = fmt.Errorf("unexpected status code: %d, retrying...", resp.StatusCode)
 			continue
@@ -259,3 +337,32 @@ func (f *Fetcher) Fetch(ctx context.Context, urlStr string) ([]byte, error) {
 
 	return nil, lastErr
 }
+
+// readBody passes body to read, cutting it off after MaxBodyBytes and
+// recording how much was read and whether anything was left unread
+func (f *Fetcher) readBody(body io.Reader, resp *Response, read func(*Response, io.Reader) error) error {
+	counted := &countingReader{r: body, n: &resp.Bytes}
+	if f.config.MaxBodyBytes <= 0 {
+		return read(resp, counted)
+	}
+
+	if err := read(resp, io.LimitReader(counted, f.config.MaxBodyBytes)); err != nil {
+		return err
+	}
+	var next [1]byte
+	n, _ := io.ReadFull(body, next[:])
+	resp.Truncated = n > 0
+	return nil
+}
+
+// countingReader adds the number of bytes read from r to n
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
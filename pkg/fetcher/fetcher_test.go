@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -196,10 +197,14 @@ func TestFetch(t *testing.T) {
 			f := New(tt.config)
 			ctx := context.Background()
 			body, err := f.Fetch(ctx, server.URL)
+			if retries := f.Retries(); retries != int64(len(tt.statusCodes)-1) {
+				t.Errorf("Expected %d retries, got %d", len(tt.statusCodes)-1, retries)
+			}
 
 			if tt.expectedError {
-				if err == nil {
-					t.Error("Expected error but got none")
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.statusCodes[len(tt.statusCodes)-1] {
+					t.Errorf("Expected status error, got: %v", err)
 				}
 			} else {
 				if err != nil {
@@ -284,3 +289,90 @@ func TestFetchWithContext(t *testing.T) {
 		t.Errorf("Expected context deadline exceeded error, got: %v", err)
 	}
 }
+
+func TestFetchMaxBodyBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "0123456789")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		maxBodyBytes  int64
+		expectedBody  string
+		expectedTrunc bool
+	}{
+		{"No Limit", 0, "0123456789", false},
+		{"Exact Limit", 10, "0123456789", false},
+		{"Truncated", 4, "0123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(FetcherConfig{
+				RequestsPerSecond: 10,
+				Burst:             5,
+				MaxBodyBytes:      tt.maxBodyBytes,
+			})
+
+			resp, err := f.FetchResponse(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(resp.Body) != tt.expectedBody || resp.Truncated != tt.expectedTrunc {
+				t.Errorf("Expected body %q (truncated %v), got %q (truncated %v)",
+					tt.expectedBody, tt.expectedTrunc, string(resp.Body), resp.Truncated)
+			}
+			if resp.Bytes != int64(len(tt.expectedBody)) {
+				t.Errorf("Expected %d bytes read, got %d", len(tt.expectedBody), resp.Bytes)
+			}
+
+			var streamed []byte
+			resp, err = f.FetchStream(context.Background(), server.URL, func(_ *Response, body io.Reader) error {
+				streamed, err = io.ReadAll(body)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(streamed) != tt.expectedBody || resp.Truncated != tt.expectedTrunc {
+				t.Errorf("Expected streamed body %q (truncated %v), got %q (truncated %v)",
+					tt.expectedBody, tt.expectedTrunc, string(streamed), resp.Truncated)
+			}
+			if resp.Bytes != int64(len(tt.expectedBody)) {
+				t.Errorf("Expected %d bytes streamed, got %d", len(tt.expectedBody), resp.Bytes)
+			}
+		})
+	}
+}
+
+func TestFetchStreamDoesNotRetryReadErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		io.WriteString(w, "body")
+	}))
+	defer server.Close()
+
+	f := New(FetcherConfig{
+		RequestsPerSecond: 10,
+		Burst:             5,
+		MaxRetries:        2,
+		InitialBackoff:    10 * time.Millisecond,
+	})
+
+	readErr := errors.New("parse failed")
+	resp, err := f.FetchStream(context.Background(), server.URL, func(_ *Response, body io.Reader) error {
+		io.ReadAll(body)
+		return readErr
+	})
+	if !errors.Is(err, readErr) {
+		t.Errorf("Expected read error, got: %v", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusOK || resp.Bytes != int64(len("body")) {
+		t.Errorf("Expected the response read so far, got %+v", resp)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
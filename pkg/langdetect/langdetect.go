@@ -0,0 +1,147 @@
+// pkg/langdetect/langdetect.go
+package langdetect
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Sample texts used to build the built-in language profiles
+//
+//go:embed profiles/*.txt
+var profileFS embed.FS
+
+const (
+	// profileSize is the number of ranked n-grams kept per profile
+	profileSize = 300
+	// maxNGram is the longest n-gram considered
+	maxNGram = 3
+	// minLetters is the least amount of text needed to attempt detection
+	minLetters = 20
+)
+
+// Detector identifies the language of a text by comparing its character
+// n-gram profile against the built-in language profiles using the
+// Cavnar-Trenkle out-of-place measure. It works entirely offline.
+type Detector struct {
+	profiles map[string]map[string]int
+}
+
+// New creates a detector with a profile for every built-in language
+func New() (*Detector, error) {
+	entries, err := profileFS.ReadDir("profiles")
+	if err != nil {
+		return nil, fmt.Errorf("error reading language profiles: %w", err)
+	}
+
+	d := &Detector{profiles: make(map[string]map[string]int)}
+	for _, entry := range entries {
+		content, err := profileFS.ReadFile(path.Join("profiles", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading language profile %s: %w", entry.Name(), err)
+		}
+		lang := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		d.profiles[lang] = buildProfile(strings.Fields(string(content)))
+	}
+
+	return d, nil
+}
+
+// Languages returns the codes of the languages the detector knows, sorted
+func (d *Detector) Languages() []string {
+	langs := make([]string, 0, len(d.profiles))
+	for lang := range d.profiles {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Detect returns the most likely language of words and a confidence between
+// 0 and 1. It returns an empty language if there is too little text.
+func (d *Detector) Detect(words []string) (string, float64) {
+	letters := 0
+	for _, word := range words {
+		letters += len(word)
+	}
+	if letters < minLetters || len(d.profiles) == 0 {
+		return "", 0
+	}
+
+	doc := buildProfile(words)
+	maxDistance := len(doc) * profileSize
+
+	best, bestDistance := "", maxDistance+1
+	for _, lang := range d.Languages() {
+		if dist := distance(doc, d.profiles[lang]); dist < bestDistance {
+			best, bestDistance = lang, dist
+		}
+	}
+
+	return best, 1 - float64(bestDistance)/float64(maxDistance)
+}
+
+// buildProfile ranks the n-grams of words by frequency, keeping the top ones
+func buildProfile(words []string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range words {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, word)
+		if word == "" {
+			continue
+		}
+
+		runes := []rune("_" + word + "_")
+		for n := 1; n <= maxNGram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				counts[string(runes[i:i+n])]++
+			}
+		}
+	}
+
+	ngrams := make([]string, 0, len(counts))
+	for ngram := range counts {
+		ngrams = append(ngrams, ngram)
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		if counts[ngrams[i]] == counts[ngrams[j]] {
+			return ngrams[i] < ngrams[j]
+		}
+		return counts[ngrams[i]] > counts[ngrams[j]]
+	})
+	if len(ngrams) > profileSize {
+		ngrams = ngrams[:profileSize]
+	}
+
+	profile := make(map[string]int, len(ngrams))
+	for rank, ngram := range ngrams {
+		profile[ngram] = rank
+	}
+	return profile
+}
+
+// distance computes the out-of-place measure between two profiles
+func distance(doc, lang map[string]int) int {
+	total := 0
+	for ngram, rank := range doc {
+		langRank, ok := lang[ngram]
+		if !ok {
+			total += profileSize
+			continue
+		}
+		if rank > langRank {
+			total += rank - langRank
+		} else {
+			total += langRank - rank
+		}
+	}
+	return total
+}
@@ -0,0 +1,54 @@
+package langdetect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "English",
+			text:     "The new update makes the battery last longer and adds several features that users have been asking for",
+			expected: "en",
+		},
+		{
+			name:     "Spanish",
+			text:     "La nueva actualización hace que la batería dure más y añade varias funciones que los usuarios habían pedido",
+			expected: "es",
+		},
+		{
+			name:     "French",
+			text:     "La nouvelle mise à jour permet à la batterie de durer plus longtemps et ajoute plusieurs fonctions demandées par les utilisateurs",
+			expected: "fr",
+		},
+		{
+			name:     "German",
+			text:     "Das neue Update sorgt dafür, dass der Akku länger hält, und fügt mehrere Funktionen hinzu, die sich die Nutzer gewünscht haben",
+			expected: "de",
+		},
+		{
+			name:     "Too Short",
+			text:     "hello",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := d.Detect(strings.Fields(tt.text))
+			if got != tt.expected {
+				t.Errorf("Detect() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// pkg/parser/charset.go
+package parser
+
+import (
+	"fmt"
+
+	"golang.org/x/net/html/charset"
+)
+
+// DecodeToUTF8 transcodes content to UTF-8. The encoding is taken from the
+// Content-Type charset, then a <meta charset> declaration, and finally by
+// sniffing the bytes. It returns the decoded content and the encoding name.
+func DecodeToUTF8(content []byte, contentType string) ([]byte, string, error) {
+	enc, name, _ := charset.DetermineEncoding(content, contentType)
+	if name == "utf-8" {
+		return content, name, nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return nil, name, fmt.Errorf("error decoding %s content: %w", name, err)
+	}
+	return decoded, name, nil
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeToUTF8(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      []byte
+		contentType  string
+		expected     string
+		expectedName string
+	}{
+		{
+			name:         "UTF-8 Content",
+			content:      []byte("<html><body>café</body></html>"),
+			contentType:  "text/html; charset=utf-8",
+			expected:     "<html><body>café</body></html>",
+			expectedName: "utf-8",
+		},
+		{
+			name:         "Latin-1 From Content-Type",
+			content:      []byte("<html><body>caf\xe9</body></html>"),
+			contentType:  "text/html; charset=ISO-8859-1",
+			expected:     "<html><body>café</body></html>",
+			expectedName: "windows-1252",
+		},
+		{
+			name:         "Meta Charset",
+			content:      []byte("<html><head><meta charset=\"windows-1252\"></head><body>na\xefve</body></html>"),
+			contentType:  "text/html",
+			expected:     "<html><head><meta charset=\"windows-1252\"></head><body>naïve</body></html>",
+			expectedName: "windows-1252",
+		},
+		{
+			name:         "Sniffed Non-UTF-8",
+			content:      []byte("<html><body>r\xe9sum\xe9</body></html>"),
+			contentType:  "",
+			expected:     "<html><body>résumé</body></html>",
+			expectedName: "windows-1252",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, name, err := DecodeToUTF8(tt.content, tt.contentType)
+			if err != nil {
+				t.Fatalf("DecodeToUTF8() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("DecodeToUTF8() = %q, want %q", got, tt.expected)
+			}
+			if name != tt.expectedName {
+				t.Errorf("DecodeToUTF8() encoding = %q, want %q", name, tt.expectedName)
+			}
+		})
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	p := New()
+	doc, err := p.ParseDocument([]byte("<html><body>Caf\xe9 cr\xe8me</body></html>"), "text/html; charset=iso-8859-1", "")
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+
+	expected := []string{"café", "crème"}
+	if !reflect.DeepEqual(doc.Words(), expected) {
+		t.Errorf("ParseDocument() words = %v, want %v", doc.Words(), expected)
+	}
+	if doc.Encoding != "windows-1252" {
+		t.Errorf("ParseDocument() encoding = %q, want %q", doc.Encoding, "windows-1252")
+	}
+}
@@ -0,0 +1,105 @@
+// pkg/parser/content.go
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedContentType is returned by ParseDocument for bodies whose
+// content type has no handler
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// UnsupportedTypeError reports the media type of a body that has no handler.
+// It matches ErrUnsupportedContentType with errors.Is.
+type UnsupportedTypeError struct {
+	MediaType string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnsupportedContentType, e.MediaType)
+}
+
+func (e *UnsupportedTypeError) Is(target error) bool {
+	return target == ErrUnsupportedContentType
+}
+
+// contentHandler extracts a document from a body of a given media type
+type contentHandler func(p *Parser, content []byte, contentType, pageURL string) (*Document, error)
+
+// contentHandlers maps media types to the handler that parses them
+var contentHandlers = map[string]contentHandler{
+	"text/html":             (*Parser).parseHTML,
+	"application/xhtml+xml": (*Parser).parseHTML,
+	"text/plain":            (*Parser).parseText,
+	"text/markdown":         (*Parser).parseMarkdown,
+	"text/x-markdown":       (*Parser).parseMarkdown,
+	"application/xml":       (*Parser).parseXML,
+	"text/xml":              (*Parser).parseXML,
+	"application/rss+xml":   (*Parser).parseXML,
+	"application/atom+xml":  (*Parser).parseXML,
+	"application/pdf":       (*Parser).parsePDF,
+}
+
+// extensionTypes maps file extensions to media types for bodies served
+// without a specific Content-Type
+var extensionTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".txt":      "text/plain",
+	".xml":      "application/xml",
+	".pdf":      "application/pdf",
+}
+
+// ParseDocument dispatches content to the handler for its media type and
+// extracts its words. Bodies of unsupported types yield an
+// *UnsupportedTypeError.
+func (p *Parser) ParseDocument(content []byte, contentType, pageURL string) (*Document, error) {
+	mediaType := DetectMediaType(content, contentType, pageURL)
+	handler, ok := contentHandlers[mediaType]
+	if !ok {
+		return nil, &UnsupportedTypeError{MediaType: mediaType}
+	}
+
+	doc, err := handler(p, content, contentType, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	doc.ContentType = mediaType
+	return doc, nil
+}
+
+// DetectMediaType returns the media type of a body from its Content-Type
+// header. Generic or missing types are refined using the URL's file
+// extension and by sniffing the content.
+func DetectMediaType(content []byte, contentType, pageURL string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	mediaType = strings.ToLower(mediaType)
+
+	switch mediaType {
+	case "", "text/plain", "application/octet-stream", "binary/octet-stream":
+	default:
+		return mediaType
+	}
+
+	if u, err := url.Parse(pageURL); err == nil {
+		if extType, ok := extensionTypes[strings.ToLower(path.Ext(u.Path))]; ok {
+			return extType
+		}
+	}
+
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	if mediaType == "text/plain" && sniffed != "text/html" {
+		// Trust the declared type unless the body is clearly HTML
+		return mediaType
+	}
+	return sniffed
+}
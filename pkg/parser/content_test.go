@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDetectMediaType(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		pageURL     string
+		expected    string
+	}{
+		{"Declared HTML", "<p>hi</p>", "text/html; charset=utf-8", "", "text/html"},
+		{"Declared PDF", "%PDF-1.4", "application/pdf", "", "application/pdf"},
+		{"Markdown Extension", "# Title", "text/plain", "https://example.com/notes.md", "text/markdown"},
+		{"Plain Text", "just words", "text/plain; charset=utf-8", "", "text/plain"},
+		{"HTML Served As Text", "<!DOCTYPE html><html></html>", "text/plain", "", "text/html"},
+		{"Sniffed PDF", "%PDF-1.7", "", "", "application/pdf"},
+		{"Sniffed XML", "<?xml version=\"1.0\"?><rss></rss>", "application/octet-stream", "", "text/xml"},
+		{"Unknown Binary", "\x00\x01\x02", "application/octet-stream", "", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMediaType([]byte(tt.content), tt.contentType, tt.pageURL); got != tt.expected {
+				t.Errorf("DetectMediaType() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDocumentContentTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		expected    []Token
+		title       string
+	}{
+		{
+			name:        "Plain Text",
+			content:     "Press release: new product",
+			contentType: "text/plain",
+			expected: []Token{
+				{Word: "press", Region: RegionBody},
+				{Word: "release", Region: RegionBody},
+				{Word: "new", Region: RegionBody},
+				{Word: "product", Region: RegionBody},
+			},
+		},
+		{
+			name: "Markdown",
+			content: "# Launch Notes\n\nSee [the docs](https://example.com/docs) and `code`.\n\n" +
+				"```\nignored block\n```\n![Product photo](photo.png)\n",
+			contentType: "text/markdown",
+			expected: []Token{
+				{Word: "launch", Region: RegionHeadings},
+				{Word: "notes", Region: RegionHeadings},
+				{Word: "see", Region: RegionBody},
+				{Word: "the", Region: RegionBody},
+				{Word: "docs", Region: RegionBody},
+				{Word: "and", Region: RegionBody},
+			},
+			title: "Launch Notes",
+		},
+		{
+			name:        "XML Transcript",
+			content:     `<?xml version="1.0"?><transcript><title>Earnings Call</title><line speaker="ceo">Revenue grew</line></transcript>`,
+			contentType: "application/xml",
+			expected: []Token{
+				{Word: "earnings", Region: RegionTitle},
+				{Word: "call", Region: RegionTitle},
+				{Word: "revenue", Region: RegionBody},
+				{Word: "grew", Region: RegionBody},
+			},
+			title: "Earnings Call",
+		},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := p.ParseDocument([]byte(tt.content), tt.contentType, "")
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+			if !reflect.DeepEqual(doc.Tokens, tt.expected) {
+				t.Errorf("ParseDocument() tokens = %v, want %v", doc.Tokens, tt.expected)
+			}
+			if doc.Metadata.Title != tt.title {
+				t.Errorf("ParseDocument() title = %q, want %q", doc.Metadata.Title, tt.title)
+			}
+		})
+	}
+}
+
+func TestParseDocumentUnsupported(t *testing.T) {
+	_, err := New().ParseDocument([]byte("\x89PNG\r\n\x1a\n"), "image/png", "")
+	if !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("ParseDocument() error = %v, want ErrUnsupportedContentType", err)
+	}
+}
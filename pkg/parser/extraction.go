@@ -0,0 +1,65 @@
+// pkg/parser/extraction.go
+package parser
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Extraction strategies
+const (
+	// StrategyDOM counts the text nodes of the whole page
+	StrategyDOM = "dom"
+	// StrategyJSONLD counts the articleBody of the page's JSON-LD article,
+	// falling back to StrategyDOM when there is none
+	StrategyJSONLD = "jsonld"
+)
+
+// strategyFor returns the extraction strategy configured for pageURL's domain
+func (p *Parser) strategyFor(pageURL string) string {
+	if u, err := url.Parse(pageURL); err == nil && u.Hostname() != "" {
+		host := strings.ToLower(u.Hostname())
+		best, bestLen := "", 0
+		for domain, strategy := range p.config.DomainStrategies {
+			domain = strings.ToLower(domain)
+			if (host == domain || strings.HasSuffix(host, "."+domain)) && len(domain) > bestLen {
+				best, bestLen = strategy, len(domain)
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+
+	if p.config.Strategy == "" {
+		return StrategyDOM
+	}
+	return p.config.Strategy
+}
+
+// extractBody returns the article tokens using strategy, along with the
+// strategy that actually produced them. With StrategyJSONLD the articleBody
+// replaces the page's body and navigation text, while the other regions are
+// still taken from the page.
+func (p *Parser) extractBody(root *html.Node, strategy string) ([]Token, string) {
+	enabled := p.enabledRegions()
+	if strategy == StrategyJSONLD {
+		if article := findJSONLDArticle(root); article != nil {
+			if body := jsonString(article["articleBody"]); body != "" {
+				pageRegions := make(map[Region]bool, len(enabled))
+				for region, on := range enabled {
+					pageRegions[region] = on && region != RegionBody && region != RegionNavigation
+				}
+
+				tokens := p.extractTokens(root, pageRegions)
+				if enabled[RegionBody] {
+					tokens = p.appendTokens(tokens, body, RegionBody)
+				}
+				return tokens, StrategyJSONLD
+			}
+		}
+	}
+	return p.extractTokens(root, enabled), StrategyDOM
+}
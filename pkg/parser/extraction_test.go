@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocumentStrategy(t *testing.T) {
+	withJSONLD := `<html><head><script type="application/ld+json">{"@type":"NewsArticle","articleBody":"Clean article body"}</script></head>
+<body><nav>Subscribe now</nav><p>Clean article body</p></body></html>`
+	withoutJSONLD := `<html><body><nav>Subscribe now</nav><p>Article body</p></body></html>`
+
+	config := ParserConfig{
+		DomainStrategies: map[string]string{"engadget.com": StrategyJSONLD},
+	}
+
+	tests := []struct {
+		name             string
+		content          string
+		pageURL          string
+		expected         []string
+		expectedStrategy string
+	}{
+		{
+			name:             "Default DOM Strategy",
+			content:          withJSONLD,
+			pageURL:          "https://example.com/story",
+			expected:         []string{"subscribe", "now", "clean", "article", "body"},
+			expectedStrategy: StrategyDOM,
+		},
+		{
+			name:             "JSON-LD For Subdomain",
+			content:          withJSONLD,
+			pageURL:          "https://www.engadget.com/story",
+			expected:         []string{"clean", "article", "body"},
+			expectedStrategy: StrategyJSONLD,
+		},
+		{
+			name:             "JSON-LD Falls Back To DOM",
+			content:          withoutJSONLD,
+			pageURL:          "https://www.engadget.com/story",
+			expected:         []string{"subscribe", "now", "article", "body"},
+			expectedStrategy: StrategyDOM,
+		},
+	}
+
+	p := NewWithConfig(config)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := p.ParseDocument([]byte(tt.content), "text/html", tt.pageURL)
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+			if !reflect.DeepEqual(doc.Words(), tt.expected) {
+				t.Errorf("ParseDocument() words = %v, want %v", doc.Words(), tt.expected)
+			}
+			if doc.Strategy != tt.expectedStrategy {
+				t.Errorf("ParseDocument() strategy = %q, want %q", doc.Strategy, tt.expectedStrategy)
+			}
+		})
+	}
+}
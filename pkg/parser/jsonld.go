@@ -0,0 +1,123 @@
+// pkg/parser/jsonld.go
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// articleTypes are the schema.org types treated as an article's JSON-LD block
+var articleTypes = map[string]bool{
+	"Article":              true,
+	"NewsArticle":          true,
+	"ReportageNewsArticle": true,
+	"AnalysisNewsArticle":  true,
+	"BlogPosting":          true,
+	"TechArticle":          true,
+}
+
+// findJSONLDArticle returns the first JSON-LD object of an article type in
+// the document, or nil if there is none
+func findJSONLDArticle(doc *html.Node) map[string]interface{} {
+	var article map[string]interface{}
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if article != nil {
+			return
+		}
+		if n.Type == html.ElementNode && isJSONLDScript(n.Data, n.Attr) {
+			if n.FirstChild != nil {
+				article = articleFromJSONLD([]byte(n.FirstChild.Data))
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+	return article
+}
+
+// articleFromJSONLD decodes a JSON-LD block, which may be a single object,
+// an array or an @graph, and returns the first article object in it
+func articleFromJSONLD(content []byte) map[string]interface{} {
+	var data interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil
+	}
+
+	var search func(interface{}) map[string]interface{}
+	search = func(v interface{}) map[string]interface{} {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if found := search(item); found != nil {
+					return found
+				}
+			}
+		case map[string]interface{}:
+			for _, t := range jsonStrings(v["@type"]) {
+				if articleTypes[t] {
+					return v
+				}
+			}
+			if graph, ok := v["@graph"]; ok {
+				return search(graph)
+			}
+		}
+		return nil
+	}
+	return search(data)
+}
+
+// jsonString returns v as a string, using the name or @id of an object
+func jsonString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]interface{}:
+		if name := jsonString(v["name"]); name != "" {
+			return name
+		}
+		return jsonString(v["@id"])
+	case []interface{}:
+		if len(v) > 0 {
+			return jsonString(v[0])
+		}
+	}
+	return ""
+}
+
+// jsonStrings returns every string in v, which may be a single value or an array
+func jsonStrings(v interface{}) []string {
+	var values []string
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, jsonStrings(item)...)
+		}
+	default:
+		if s := jsonString(v); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// isJSONLDScript reports whether an element is a JSON-LD script block
+func isJSONLDScript(tag string, attrs []html.Attribute) bool {
+	return tag == "script" && strings.EqualFold(attrValue(attrs, "type"), "application/ld+json")
+}
+
+// attrValue returns the value of the named attribute in attrs
+func attrValue(attrs []html.Attribute, name string) string {
+	for _, a := range attrs {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+// pkg/parser/metadata.go
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+)
+
+// metadataCollector accumulates article metadata from the elements of a
+// page, so that it can be fed from both a parsed DOM and a token stream
+type metadataCollector struct {
+	article   map[string]interface{}
+	title     string
+	canonical string
+	metaTags  map[string][]string
+}
+
+func newMetadataCollector() *metadataCollector {
+	return &metadataCollector{metaTags: make(map[string][]string)}
+}
+
+// element records the metadata carried by an element's attributes
+func (c *metadataCollector) element(tag string, attrs []html.Attribute) {
+	switch tag {
+	case "link":
+		if c.canonical == "" && strings.EqualFold(attrValue(attrs, "rel"), "canonical") {
+			c.canonical = strings.TrimSpace(attrValue(attrs, "href"))
+		}
+	case "meta":
+		key := attrValue(attrs, "property")
+		if key == "" {
+			key = attrValue(attrs, "name")
+		}
+		if content := strings.TrimSpace(attrValue(attrs, "content")); key != "" && content != "" {
+			key = strings.ToLower(key)
+			c.metaTags[key] = append(c.metaTags[key], content)
+		}
+	}
+}
+
+// titleText records the text of the <title> element
+func (c *metadataCollector) titleText(text string) {
+	if c.title == "" {
+		c.title = strings.TrimSpace(text)
+	}
+}
+
+// jsonLD records the first article found in the page's JSON-LD blocks
+func (c *metadataCollector) jsonLD(content []byte) {
+	if c.article == nil {
+		c.article = articleFromJSONLD(content)
+	}
+}
+
+// metadata resolves the collected values. JSON-LD values win over meta
+// tags, which win over the plain HTML elements.
+func (c *metadataCollector) metadata() models.ArticleMetadata {
+	var meta models.ArticleMetadata
+	if article := c.article; article != nil {
+		meta.Title = jsonString(article["headline"])
+		meta.Author = strings.Join(jsonStrings(article["author"]), ", ")
+		meta.PublishedAt = jsonString(article["datePublished"])
+		meta.CanonicalURL = jsonString(article["mainEntityOfPage"])
+		if meta.CanonicalURL == "" {
+			meta.CanonicalURL = jsonString(article["url"])
+		}
+		meta.Section = strings.Join(jsonStrings(article["articleSection"]), ", ")
+		meta.Tags = splitKeywords(jsonStrings(article["keywords"]))
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if values := c.metaTags[key]; len(values) > 0 {
+				return values[0]
+			}
+		}
+		return ""
+	}
+
+	if meta.Title == "" {
+		meta.Title = first("og:title", "twitter:title")
+	}
+	if meta.Title == "" {
+		meta.Title = c.title
+	}
+	if meta.Author == "" {
+		meta.Author = first("author", "article:author", "parsely-author")
+	}
+	if meta.PublishedAt == "" {
+		meta.PublishedAt = first("article:published_time", "parsely-pub-date", "date")
+	}
+	if meta.CanonicalURL == "" {
+		meta.CanonicalURL = c.canonical
+	}
+	if meta.CanonicalURL == "" {
+		meta.CanonicalURL = first("og:url")
+	}
+	if meta.Section == "" {
+		meta.Section = first("article:section", "parsely-section")
+	}
+	if len(meta.Tags) == 0 {
+		meta.Tags = c.metaTags["article:tag"]
+	}
+	if len(meta.Tags) == 0 {
+		meta.Tags = splitKeywords(c.metaTags["keywords"])
+	}
+
+	return meta
+}
+
+// extractMetadata collects article metadata from JSON-LD, OpenGraph and
+// standard meta tags, and the <title> and canonical link of a parsed page
+func extractMetadata(doc *html.Node) models.ArticleMetadata {
+	c := newMetadataCollector()
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			c.element(n.Data, n.Attr)
+			switch {
+			case n.Data == "title" && n.FirstChild != nil:
+				c.titleText(n.FirstChild.Data)
+			case isJSONLDScript(n.Data, n.Attr) && n.FirstChild != nil:
+				c.jsonLD([]byte(n.FirstChild.Data))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+
+	return c.metadata()
+}
+
+// splitKeywords splits comma-separated keyword lists into individual tags
+func splitKeywords(values []string) []string {
+	var tags []string
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NivBraz/wordcount-service/internal/models"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected models.ArticleMetadata
+	}{
+		{
+			name: "JSON-LD NewsArticle",
+			content: `<html><head><title>Page Title</title>
+<script type="application/ld+json">{"@context":"https://schema.org","@graph":[{"@type":"WebPage"},{"@type":"NewsArticle","headline":"JSON Headline","author":[{"@type":"Person","name":"Jane Doe"},{"@type":"Person","name":"John Roe"}],"datePublished":"2019-08-25T10:00:00Z","mainEntityOfPage":{"@id":"https://example.com/story"},"articleSection":"Gear","keywords":["sony","yamaha"]}]}</script>
+</head><body>Hello</body></html>`,
+			expected: models.ArticleMetadata{
+				Title:        "JSON Headline",
+				Author:       "Jane Doe, John Roe",
+				PublishedAt:  "2019-08-25T10:00:00Z",
+				CanonicalURL: "https://example.com/story",
+				Section:      "Gear",
+				Tags:         []string{"sony", "yamaha"},
+			},
+		},
+		{
+			name: "OpenGraph and Meta Tags",
+			content: `<html><head><title>Page Title</title>
+<meta property="og:title" content="OG Title">
+<meta name="author" content="Jane Doe">
+<meta property="article:published_time" content="2019-08-24">
+<meta property="article:section" content="Science">
+<meta property="article:tag" content="space">
+<meta property="article:tag" content="crime">
+<link rel="canonical" href="https://example.com/canonical">
+</head><body>Hello</body></html>`,
+			expected: models.ArticleMetadata{
+				Title:        "OG Title",
+				Author:       "Jane Doe",
+				PublishedAt:  "2019-08-24",
+				CanonicalURL: "https://example.com/canonical",
+				Section:      "Science",
+				Tags:         []string{"space", "crime"},
+			},
+		},
+		{
+			name:    "Title and Keywords Only",
+			content: `<html><head><title> Page Title </title><meta name="keywords" content="one, two,three"></head><body>Hello</body></html>`,
+			expected: models.ArticleMetadata{
+				Title: "Page Title",
+				Tags:  []string{"one", "two", "three"},
+			},
+		},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := p.ParseDocument([]byte(tt.content), "text/html", "")
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+			if !reflect.DeepEqual(doc.Metadata, tt.expected) {
+				t.Errorf("ParseDocument() metadata = %+v, want %+v", doc.Metadata, tt.expected)
+			}
+		})
+	}
+}
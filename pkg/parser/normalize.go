@@ -0,0 +1,75 @@
+// pkg/parser/normalize.go
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Supported Unicode normalization forms
+const (
+	NormalizationNone = ""
+	NormalizationNFC  = "nfc"
+	NormalizationNFKC = "nfkc"
+)
+
+// CleaningKey identifies the settings Normalize and CleanWord depend on, so
+// that words cleaned under different settings are not mixed up
+func (p *Parser) CleaningKey() string {
+	key := "normalization=" + p.config.Normalization
+	if p.config.FoldDiacritics {
+		key += ",fold"
+	}
+	return key
+}
+
+// Normalize lowercases word and applies the configured Unicode normalization
+// and diacritic folding. The word bank and article tokens must both go
+// through Normalize so that equivalent spellings compare equal.
+func (p *Parser) Normalize(word string) string {
+	word = strings.ToLower(word)
+	if isASCII(word) {
+		// ASCII is unaffected by normalization and folding
+		return word
+	}
+
+	var form, decomposed norm.Form
+	switch p.config.Normalization {
+	case NormalizationNFC:
+		form, decomposed = norm.NFC, norm.NFD
+	case NormalizationNFKC:
+		form, decomposed = norm.NFKC, norm.NFKD
+	default:
+		if !p.config.FoldDiacritics {
+			return word
+		}
+		form, decomposed = norm.NFC, norm.NFD
+	}
+
+	if !p.config.FoldDiacritics {
+		return form.String(word)
+	}
+
+	// Decompose, drop the combining marks and recompose. Transformers keep
+	// state, so the chain is built per call to stay safe for concurrent use.
+	t := transform.Chain(decomposed, runes.Remove(runes.In(unicode.Mn)), form)
+	folded, _, err := transform.String(t, word)
+	if err != nil {
+		return word
+	}
+	return folded
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ParserConfig
+		input    string
+		expected string
+	}{
+		{
+			name:     "ASCII Word",
+			config:   ParserConfig{Normalization: NormalizationNFC, FoldDiacritics: true},
+			input:    "Hello",
+			expected: "hello",
+		},
+		{
+			name:     "No Normalization",
+			config:   ParserConfig{},
+			input:    "cafe\u0301",
+			expected: "cafe\u0301",
+		},
+		{
+			name:     "NFC Composes Combining Accent",
+			config:   ParserConfig{Normalization: NormalizationNFC},
+			input:    "cafe\u0301",
+			expected: "caf\u00e9",
+		},
+		{
+			name:     "NFKC Expands Ligature",
+			config:   ParserConfig{Normalization: NormalizationNFKC},
+			input:    "ﬁnance",
+			expected: "finance",
+		},
+		{
+			name:     "Fold Precomposed",
+			config:   ParserConfig{Normalization: NormalizationNFC, FoldDiacritics: true},
+			input:    "Na\u00efve",
+			expected: "naive",
+		},
+		{
+			name:     "Fold Combining",
+			config:   ParserConfig{FoldDiacritics: true},
+			input:    "cafe\u0301",
+			expected: "cafe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithConfig(tt.config)
+			if got := p.Normalize(tt.input); got != tt.expected {
+				t.Errorf("Normalize() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWordsNormalization(t *testing.T) {
+	p := NewWithConfig(ParserConfig{Normalization: NormalizationNFC})
+	got, err := p.ParseWords([]byte("<html><body>caf\u00e9 cafe\u0301</body></html>"))
+	if err != nil {
+		t.Fatalf("ParseWords() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != got[1] {
+		t.Errorf("ParseWords() = %q, want both spellings to be equal", got)
+	}
+}
@@ -11,13 +11,66 @@ import (
 	"github.com/NivBraz/wordcount-service/internal/models"
 )
 
-type Parser struct{}
+// ParserConfig controls how extracted words are normalized
+type ParserConfig struct {
+	// Normalization is the Unicode normalization form applied to every
+	// word before cleaning: "nfc", "nfkc" or empty for none
+	Normalization string
+	// FoldDiacritics strips combining marks so that "naïve" becomes "naive"
+	FoldDiacritics bool
+	// Strategy is the default extraction strategy, StrategyDOM if empty
+	Strategy string
+	// DomainStrategies overrides Strategy for a domain and its subdomains
+	DomainStrategies map[string]string
+	// Regions are the document regions to extract words from, DefaultRegions if empty
+	Regions map[Region]bool
+}
+
+type Parser struct {
+	config ParserConfig
+}
 
 func New() *Parser {
-	return &Parser{}
+	return NewWithConfig(ParserConfig{})
+}
+
+// NewWithConfig creates a parser that normalizes words according to config
+func NewWithConfig(config ParserConfig) *Parser {
+	return &Parser{config: config}
+}
+
+// Document holds the words and details extracted from a single article
+type Document struct {
+	Tokens      []Token
+	ContentType string
+	Encoding    string
+	Strategy    string
+	Metadata    models.ArticleMetadata
+}
+
+// parseHTML decodes HTML content to UTF-8 and extracts its words with the
+// strategy configured for pageURL's domain
+func (p *Parser) parseHTML(content []byte, contentType, pageURL string) (*Document, error) {
+	decoded, encoding, err := DecodeToUTF8(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := html.Parse(bytes.NewReader(decoded))
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, strategy := p.extractBody(root, p.strategyFor(pageURL))
+	return &Document{
+		Tokens:   tokens,
+		Encoding: encoding,
+		Strategy: strategy,
+		Metadata: extractMetadata(root),
+	}, nil
 }
 
-// ParseWords extracts words from HTML content
+// ParseWords extracts words from UTF-8 HTML content
 func (p *Parser) ParseWords(content []byte) ([]string, error) {
 	doc, err := html.Parse(bytes.NewReader(content))
 	if err != nil {
@@ -25,48 +78,69 @@ func (p *Parser) ParseWords(content []byte) ([]string, error) {
 	}
 
 	var words []string
-	var extractText func(*html.Node)
-	extractText = func(n *html.Node) {
-		// if its script or style ignore
-		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
-			return
+	for _, token := range p.extractTokens(doc, DefaultRegions) {
+		words = append(words, token.Word)
+	}
+	return words, nil
+}
+
+// Words returns the words of every token of the document
+func (d *Document) Words() []string {
+	words := make([]string, len(d.Tokens))
+	for i, token := range d.Tokens {
+		words[i] = token.Word
+	}
+	return words
+}
+
+// appendWords splits text into words and appends the cleaned ones to words
+func (p *Parser) appendWords(words []string, text string) []string {
+	for _, word := range strings.Fields(text) {
+		// Clean and normalize the word
+		word = cleanWord(p.Normalize(word))
+		if word != "" {
+			words = append(words, word)
 		}
-		if n.Type == html.TextNode {
-			// Split text into words
-			text := strings.Fields(n.Data)
-			for _, word := range text {
-				// Clean and normalize the word
-				word = cleanWord(word)
-				if word != "" {
-					words = append(words, word)
-				}
-			}
+	}
+	return words
+}
+
+// ParseWordBank extracts words from the word bank content, one per line.
+// Lines starting with # are comments.
+func (p *Parser) ParseWordBank(content []byte) ([]string, error) {
+	// Split content into lines
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	var words []string
+
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
 		}
-		// Recursively process child nodes
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			extractText(c)
+		if word := p.CleanWord(line); word != "" {
+			words = append(words, word)
 		}
 	}
 
-	extractText(doc)
 	return words, nil
 }
 
-// ParseWordBank extracts words from the word bank content
-func (p *Parser) ParseWordBank(content []byte) ([]string, error) {
-	// Split content into lines
-	lines := strings.Split(string(content), "\n")
+// CleanWords normalizes and cleans words the way article text is, dropping
+// the ones left empty
+func (p *Parser) CleanWords(lines []string) []string {
 	var words []string
 
 	for _, line := range lines {
-		// Clean and normalize the word
-		word := cleanWord(line)
-		if word != "" {
+		if word := p.CleanWord(line); word != "" {
 			words = append(words, word)
 		}
 	}
 
-	return words, nil
+	return words
+}
+
+// CleanWord normalizes and cleans a single word the way article text is
+func (p *Parser) CleanWord(word string) string {
+	return cleanWord(p.Normalize(word))
 }
 
 // cleanWord normalizes and cleans a word
@@ -94,6 +94,18 @@ func TestParseWordBank(t *testing.T) {
 			expected: []string{"hello", "world"},
 			wantErr:  false,
 		},
+		{
+			name:     "CRLF Line Endings",
+			content:  []byte("hello\r\nworld\r\n"),
+			expected: []string{"hello", "world"},
+			wantErr:  false,
+		},
+		{
+			name:     "Comments",
+			content:  []byte("# English words\nhello\n  # skipped\nworld"),
+			expected: []string{"hello", "world"},
+			wantErr:  false,
+		},
 		{
 			name:     "Empty Content",
 			content:  []byte(""),
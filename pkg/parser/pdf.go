@@ -0,0 +1,882 @@
+// pkg/parser/pdf.go
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// PDF text extraction is best-effort: every object is read, including those
+// in compressed object streams, and the content streams of each page are
+// interpreted for their text operators only. Glyph codes are mapped through
+// the fonts' ToUnicode CMaps when present and WinAnsi otherwise. Layout is
+// ignored beyond inserting word breaks at line moves and wide TJ gaps.
+
+const (
+	// pdfMaxFormDepth limits recursion into nested form XObjects
+	pdfMaxFormDepth = 8
+	// pdfMaxRange limits the size of a single CMap bfrange
+	pdfMaxRange = 1 << 16
+	// pdfMaxCMapEntries limits the codes mapped by a single CMap
+	pdfMaxCMapEntries = 1 << 20
+	// pdfMaxInflated limits the size a single stream is inflated to
+	pdfMaxInflated = 64 << 20
+	// pdfWordGap is the TJ adjustment, in thousandths of an em, treated as a space
+	pdfWordGap = 200
+)
+
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfRef     int
+	pdfString  []byte
+	pdfArray   []interface{}
+	pdfDict    map[pdfName]interface{}
+)
+
+// pdfObject is an indirect object: its value and, for streams, the raw data
+type pdfObject struct {
+	value  interface{}
+	stream []byte
+}
+
+// pdfFile holds the indirect objects of a PDF
+type pdfFile struct {
+	objects map[int]*pdfObject
+	cmaps   map[int]*pdfCMap
+}
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// parsePDF extracts the text of a PDF body
+func (p *Parser) parsePDF(content []byte, contentType, pageURL string) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(content, "\x00\t\n\f\r "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("error parsing PDF: missing header")
+	}
+
+	f := readPDF(content)
+	var text strings.Builder
+	for _, page := range f.pages() {
+		resources, _ := f.resolve(f.inherited(page, "Resources")).(pdfDict)
+		for _, data := range f.contents(page) {
+			f.extractText(&text, data, resources, 0)
+		}
+	}
+
+	doc := &Document{Encoding: "pdf"}
+	if p.enabledRegions()[RegionBody] {
+		doc.Tokens = p.appendTokens(nil, text.String(), RegionBody)
+	}
+
+	// The document information dictionary holds the title and author
+	for _, num := range f.objectNumbers() {
+		info, ok := f.objects[num].value.(pdfDict)
+		if !ok {
+			continue
+		}
+		title, hasTitle := info["Title"].(pdfString)
+		_, hasProducer := info["Producer"]
+		_, hasCreator := info["Creator"]
+		if hasTitle && (hasProducer || hasCreator) {
+			doc.Metadata.Title = decodePDFTextString(title)
+			if author, ok := info["Author"].(pdfString); ok {
+				doc.Metadata.Author = decodePDFTextString(author)
+			}
+			break
+		}
+	}
+	if doc.Metadata.Title != "" && p.enabledRegions()[RegionTitle] {
+		doc.Tokens = append(p.appendTokens(nil, doc.Metadata.Title, RegionTitle), doc.Tokens...)
+	}
+
+	return doc, nil
+}
+
+// readPDF collects the indirect objects of data, including the objects
+// stored in object streams. Later definitions replace earlier ones, as in
+// incremental updates.
+func readPDF(data []byte) *pdfFile {
+	f := &pdfFile{
+		objects: make(map[int]*pdfObject),
+		cmaps:   make(map[int]*pdfCMap),
+	}
+
+	for _, m := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		lex := &pdfLexer{data: data, pos: m[1]}
+		obj := &pdfObject{value: lex.value()}
+		if dict, ok := obj.value.(pdfDict); ok {
+			obj.stream = lex.stream(dict)
+		}
+		f.objects[num] = obj
+	}
+
+	for _, num := range f.objectNumbers() {
+		obj := f.objects[num]
+		dict, ok := obj.value.(pdfDict)
+		if !ok || dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		f.readObjectStream(obj, dict)
+	}
+
+	return f
+}
+
+// readObjectStream adds the objects compressed in an object stream
+func (f *pdfFile) readObjectStream(obj *pdfObject, dict pdfDict) {
+	data, err := f.decodeStream(obj)
+	if err != nil {
+		return
+	}
+	n, _ := f.resolve(dict["N"]).(float64)
+	first, _ := f.resolve(dict["First"]).(float64)
+
+	header := &pdfLexer{data: data}
+	for i := 0; i < int(n); i++ {
+		num, ok1 := header.next().(float64)
+		offset, ok2 := header.next().(float64)
+		if !ok1 || !ok2 {
+			return
+		}
+		pos := int(first) + int(offset)
+		if pos < 0 || pos >= len(data) {
+			continue
+		}
+		if _, exists := f.objects[int(num)]; !exists {
+			lex := &pdfLexer{data: data, pos: pos}
+			f.objects[int(num)] = &pdfObject{value: lex.value()}
+		}
+	}
+}
+
+// objectNumbers returns the object numbers in ascending order
+func (f *pdfFile) objectNumbers() []int {
+	nums := make([]int, 0, len(f.objects))
+	for num := range f.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// resolve follows indirect references
+func (f *pdfFile) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj, ok := f.objects[int(ref)]
+		if !ok {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+// pages returns the page dictionaries in document order. Documents without
+// a usable page tree fall back to every page object in object order.
+func (f *pdfFile) pages() []pdfDict {
+	var pages []pdfDict
+	var walk func(node pdfDict, depth int)
+	walk = func(node pdfDict, depth int) {
+		// The depth limit also guards against cycles in broken files
+		if depth > 64 {
+			return
+		}
+		switch node["Type"] {
+		case pdfName("Page"):
+			pages = append(pages, node)
+		case pdfName("Pages"):
+			kids, _ := f.resolve(node["Kids"]).(pdfArray)
+			for _, kid := range kids {
+				if kidDict, ok := f.resolve(kid).(pdfDict); ok {
+					walk(kidDict, depth+1)
+				}
+			}
+		}
+	}
+
+	for _, num := range f.objectNumbers() {
+		if dict, ok := f.objects[num].value.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			if root, ok := f.resolve(dict["Pages"]).(pdfDict); ok {
+				walk(root, 0)
+			}
+			break
+		}
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	for _, num := range f.objectNumbers() {
+		if dict, ok := f.objects[num].value.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			pages = append(pages, dict)
+		}
+	}
+	return pages
+}
+
+// inherited looks up an inheritable page attribute through the page tree
+func (f *pdfFile) inherited(page pdfDict, key pdfName) interface{} {
+	for node, depth := page, 0; node != nil && depth < 64; depth++ {
+		if v, ok := node[key]; ok {
+			return v
+		}
+		node, _ = f.resolve(node["Parent"]).(pdfDict)
+	}
+	return nil
+}
+
+// contents returns the decoded content streams of a page
+func (f *pdfFile) contents(page pdfDict) [][]byte {
+	var refs pdfArray
+	switch c := page["Contents"].(type) {
+	case pdfRef:
+		if arr, ok := f.resolve(c).(pdfArray); ok {
+			refs = arr
+		} else {
+			refs = pdfArray{c}
+		}
+	case pdfArray:
+		refs = c
+	}
+
+	var streams [][]byte
+	for _, ref := range refs {
+		if r, ok := ref.(pdfRef); ok {
+			if obj, ok := f.objects[int(r)]; ok {
+				if data, err := f.decodeStream(obj); err == nil {
+					streams = append(streams, data)
+				}
+			}
+		}
+	}
+	return streams
+}
+
+// decodeStream applies the stream's filters to its raw data
+func (f *pdfFile) decodeStream(obj *pdfObject) ([]byte, error) {
+	dict, _ := obj.value.(pdfDict)
+	if obj.stream == nil || dict == nil {
+		return nil, fmt.Errorf("not a stream")
+	}
+
+	var filters pdfArray
+	switch filter := f.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = pdfArray{filter}
+	case pdfArray:
+		filters = filter
+	}
+
+	data := obj.stream
+	for _, filter := range filters {
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// Keep whatever was inflated before a truncated or corrupt end
+			// or the size limit
+			inflated, err := io.ReadAll(io.LimitReader(r, pdfMaxInflated))
+			if err != nil && len(inflated) == 0 {
+				return nil, err
+			}
+			data = inflated
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data = decodePDFHex(bytes.TrimSuffix(bytes.TrimSpace(data), []byte(">")))
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data = bytes.TrimSuffix(bytes.TrimSpace(data), []byte("~>"))
+			// Each z stands for four zero bytes
+			decoded := make([]byte, 4*len(data))
+			n, _, err := ascii85.Decode(decoded, data, true)
+			if err != nil {
+				return nil, err
+			}
+			data = decoded[:n]
+		default:
+			return nil, fmt.Errorf("unsupported filter %v", filter)
+		}
+	}
+	return data, nil
+}
+
+// pdfFont holds what is needed to map a font's glyph codes to text
+type pdfFont struct {
+	cmap      *pdfCMap
+	composite bool
+}
+
+// font loads the font registered under name in resources
+func (f *pdfFile) font(resources pdfDict, name pdfName) *pdfFont {
+	fonts, _ := f.resolve(resources["Font"]).(pdfDict)
+	ref := fonts[name]
+	dict, _ := f.resolve(ref).(pdfDict)
+	if dict == nil {
+		return nil
+	}
+
+	font := &pdfFont{composite: dict["Subtype"] == pdfName("Type0")}
+	if cmapRef, ok := dict["ToUnicode"].(pdfRef); ok {
+		if cmap, ok := f.cmaps[int(cmapRef)]; ok {
+			font.cmap = cmap
+		} else if obj, ok := f.objects[int(cmapRef)]; ok {
+			if data, err := f.decodeStream(obj); err == nil {
+				font.cmap = parsePDFCMap(data)
+				f.cmaps[int(cmapRef)] = font.cmap
+			}
+		}
+	}
+	return font
+}
+
+// extractText interprets the text operators of a content stream
+func (f *pdfFile) extractText(text *strings.Builder, data []byte, resources pdfDict, depth int) {
+	lex := &pdfLexer{data: data}
+	var operands []interface{}
+	var font *pdfFont
+
+	for {
+		v := lex.value()
+		if v == nil && lex.pos >= len(data) {
+			return
+		}
+		op, isOp := v.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, v)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = f.font(resources, name)
+				}
+			}
+		case "Tj", "'", "\"":
+			if op != "Tj" {
+				text.WriteByte('\n')
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					text.WriteString(decodePDFString(s, font))
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[len(operands)-1].(pdfArray)
+				for _, item := range arr {
+					switch item := item.(type) {
+					case pdfString:
+						text.WriteString(decodePDFString(item, font))
+					case float64:
+						if item < -pdfWordGap {
+							text.WriteByte(' ')
+						}
+					}
+				}
+			}
+		case "Td", "TD", "T*", "Tm", "BT", "ET":
+			text.WriteByte('\n')
+		case "Do":
+			if depth < pdfMaxFormDepth && len(operands) > 0 {
+				name, _ := operands[0].(pdfName)
+				xobjects, _ := f.resolve(resources["XObject"]).(pdfDict)
+				if ref, ok := xobjects[name].(pdfRef); ok {
+					f.extractForm(text, ref, resources, depth)
+				}
+			}
+		case "ID":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// extractForm interprets the content of a form XObject
+func (f *pdfFile) extractForm(text *strings.Builder, ref pdfRef, resources pdfDict, depth int) {
+	obj, ok := f.objects[int(ref)]
+	if !ok {
+		return
+	}
+	dict, _ := obj.value.(pdfDict)
+	if dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := f.decodeStream(obj)
+	if err != nil {
+		return
+	}
+	if own, ok := f.resolve(dict["Resources"]).(pdfDict); ok {
+		resources = own
+	}
+	f.extractText(text, data, resources, depth+1)
+}
+
+// decodePDFString maps the glyph codes of a shown string to text
+func decodePDFString(s pdfString, font *pdfFont) string {
+	if font != nil && font.cmap != nil {
+		return font.cmap.decode(s)
+	}
+	if font != nil && font.composite {
+		// Glyph IDs without a ToUnicode map cannot be turned into text
+		return ""
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(s)
+	if err != nil {
+		return ""
+	}
+	return string(decoded)
+}
+
+// decodePDFTextString decodes a string outside of content streams, which is
+// either UTF-16BE with a byte order mark or PDFDocEncoding
+func decodePDFTextString(s pdfString) string {
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		return decodeUTF16BE(s[2:])
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(s)
+	if err != nil {
+		return string(s)
+	}
+	return strings.TrimSpace(string(decoded))
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func decodePDFHex(b []byte) []byte {
+	digits := make([]byte, 0, len(b))
+	for _, c := range b {
+		if isPDFHexDigit(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded := make([]byte, len(digits)/2)
+	hex.Decode(decoded, digits)
+	return decoded
+}
+
+func isPDFHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// pdfCMap maps character codes of a font to Unicode text
+type pdfCMap struct {
+	codeLen int
+	chars   map[uint32]string
+}
+
+// parsePDFCMap reads the codespace, bfchar and bfrange sections of a
+// ToUnicode CMap
+func parsePDFCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{chars: make(map[uint32]string)}
+	lex := &pdfLexer{data: data}
+
+	section := func(end pdfKeyword) []interface{} {
+		var values []interface{}
+		for {
+			v := lex.value()
+			if v == nil && lex.pos >= len(data) || v == end {
+				return values
+			}
+			values = append(values, v)
+		}
+	}
+
+	for {
+		v := lex.value()
+		if v == nil && lex.pos >= len(data) {
+			break
+		}
+		switch v {
+		case pdfKeyword("begincodespacerange"):
+			values := section("endcodespacerange")
+			for _, value := range values {
+				if s, ok := value.(pdfString); ok && len(s) > cmap.codeLen {
+					cmap.codeLen = len(s)
+				}
+			}
+		case pdfKeyword("beginbfchar"):
+			values := section("endbfchar")
+			for i := 0; i+1 < len(values); i += 2 {
+				src, ok1 := values[i].(pdfString)
+				dst, ok2 := values[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				cmap.noteCodeLen(src)
+				if code, ok := pdfCode(src); ok {
+					cmap.set(code, decodeUTF16BE(dst))
+				}
+			}
+		case pdfKeyword("beginbfrange"):
+			values := section("endbfrange")
+			for i := 0; i+2 < len(values); i += 3 {
+				lo, ok1 := values[i].(pdfString)
+				hi, ok2 := values[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				cmap.noteCodeLen(lo)
+				start, okStart := pdfCode(lo)
+				end, okEnd := pdfCode(hi)
+				if !okStart || !okEnd || end < start || end-start >= pdfMaxRange {
+					continue
+				}
+				// Count rather than compare codes, which would wrap at the
+				// largest one
+				n := uint64(end-start) + 1
+				switch dst := values[i+2].(type) {
+				case pdfString:
+					for offset := uint64(0); offset < n; offset++ {
+						cmap.set(start+uint32(offset), decodeUTF16BE(offsetPDFString(dst, uint32(offset))))
+					}
+				case pdfArray:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && uint64(j) < n {
+							cmap.set(start+uint32(j), decodeUTF16BE(s))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if cmap.codeLen == 0 {
+		cmap.codeLen = 1
+	}
+	return cmap
+}
+
+// set maps code to text, ignoring new codes once the CMap is full
+func (c *pdfCMap) set(code uint32, text string) {
+	if _, ok := c.chars[code]; ok || len(c.chars) < pdfMaxCMapEntries {
+		c.chars[code] = text
+	}
+}
+
+func (c *pdfCMap) noteCodeLen(src pdfString) {
+	if c.codeLen == 0 {
+		c.codeLen = len(src)
+	}
+}
+
+// decode maps a string of character codes to text, skipping unmapped codes
+func (c *pdfCMap) decode(s pdfString) string {
+	var b strings.Builder
+	for i := 0; i+c.codeLen <= len(s); i += c.codeLen {
+		code, ok := pdfCode(s[i : i+c.codeLen])
+		if !ok {
+			continue
+		}
+		if text, ok := c.chars[code]; ok {
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+// pdfCode reads a big-endian character code, reporting false for codes
+// longer than the 4 bytes CMaps allow
+func pdfCode(s pdfString) (uint32, bool) {
+	if len(s) > 4 {
+		return 0, false
+	}
+	var code uint32
+	for _, c := range s {
+		code = code<<8 | uint32(c)
+	}
+	return code, true
+}
+
+// offsetPDFString adds offset to the last byte of a bfrange destination
+func offsetPDFString(s pdfString, offset uint32) []byte {
+	out := append([]byte(nil), s...)
+	if len(out) > 0 {
+		out[len(out)-1] += byte(offset)
+	}
+	return out
+}
+
+// pdfLexer reads PDF tokens and values from data
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// next returns the next token: a number, name, string, keyword or delimiter
+func (l *pdfLexer) next() interface{} {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return l.literalString()
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<")
+		}
+		end := bytes.IndexByte(l.data[l.pos:], '>')
+		if end < 0 {
+			end = len(l.data) - l.pos
+		}
+		s := decodePDFHex(l.data[l.pos+1 : l.pos+end])
+		l.pos = min(l.pos+end+1, len(l.data))
+		return pdfString(s)
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>")
+		}
+		l.pos++
+		return pdfKeyword(">")
+	case c == '[' || c == ']' || c == '{' || c == '}' || c == ')':
+		l.pos++
+		return pdfKeyword(string(c))
+	case c == '/':
+		l.pos++
+		return pdfName(l.regular(true))
+	}
+
+	word := l.regular(false)
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return n
+	}
+	return pdfKeyword(word)
+}
+
+// regular reads a run of regular characters, decoding #xx escapes in names
+func (l *pdfLexer) regular(name bool) string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start && l.pos < len(l.data) && !name {
+		// Skip a stray delimiter so that lexing always progresses
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if name && strings.Contains(word, "#") {
+		var b strings.Builder
+		for i := 0; i < len(word); i++ {
+			if word[i] == '#' && i+2 < len(word) {
+				if v, err := strconv.ParseUint(word[i+1:i+3], 16, 8); err == nil {
+					b.WriteByte(byte(v))
+					i += 2
+					continue
+				}
+			}
+			b.WriteByte(word[i])
+		}
+		word = b.String()
+	}
+	return word
+}
+
+// literalString reads a parenthesized string with nesting and escapes
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // opening parenthesis
+	var s []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return s
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return s
+}
+
+// value reads a complete value, assembling arrays, dictionaries and
+// indirect references. It returns nil at the end of the data.
+func (l *pdfLexer) value() interface{} {
+	tok := l.next()
+	switch tok {
+	case pdfKeyword("["):
+		arr := pdfArray{}
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.data) {
+				return arr
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return arr
+			}
+			arr = append(arr, l.value())
+		}
+	case pdfKeyword("<<"):
+		dict := pdfDict{}
+		for {
+			key := l.value()
+			if key == nil || key == pdfKeyword(">>") {
+				return dict
+			}
+			if name, ok := key.(pdfName); ok {
+				dict[name] = l.value()
+			}
+		}
+	case pdfKeyword("true"):
+		return true
+	case pdfKeyword("false"):
+		return false
+	case pdfKeyword("null"):
+		return nil
+	}
+
+	// An integer may start an indirect reference: "12 0 R"
+	if n, ok := tok.(float64); ok && n == float64(int(n)) {
+		save := l.pos
+		if gen, ok := l.next().(float64); ok && gen == float64(int(gen)) {
+			if l.next() == pdfKeyword("R") {
+				return pdfRef(int(n))
+			}
+		}
+		l.pos = save
+	}
+	return tok
+}
+
+// stream returns the raw data of the stream following a dictionary, or nil
+// if there is none
+func (l *pdfLexer) stream(dict pdfDict) []byte {
+	l.skipSpace()
+	if l.pos >= len(l.data) || !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return nil
+	}
+	start := l.pos + len("stream")
+	if start < len(l.data) && l.data[start] == '\r' {
+		start++
+	}
+	if start < len(l.data) && l.data[start] == '\n' {
+		start++
+	}
+
+	// Trust a direct /Length when it lands on the endstream keyword
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(l.data)-start) {
+		end := start + int(length)
+		if start <= end && end <= len(l.data) && bytes.HasPrefix(bytes.TrimLeft(l.data[end:], "\r\n \t"), []byte("endstream")) {
+			l.pos = end
+			return l.data[start:end]
+		}
+	}
+
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		return l.data[start:]
+	}
+	l.pos = start + end
+	return bytes.TrimRight(l.data[start:start+end], "\r\n")
+}
+
+// skipInlineImage skips the binary data of an inline image up to EI
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos + 1; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a PDF from numbered object bodies. Streams are given
+// as the dictionary followed by the raw stream data.
+func buildPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func pdfStream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func flate(data string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(data))
+	w.Close()
+	return buf.Bytes()
+}
+
+func a85(data string) []byte {
+	encoded := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	n := ascii85.Encode(encoded, []byte(data))
+	return append(encoded[:n], "~>"...)
+}
+
+func TestParsePDF(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <0053>
+<0002> <0070>
+endbfchar
+1 beginbfrange
+<0003> <0005> <0061>
+endbfrange
+endcmap`
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected []string
+		title    string
+	}{
+		{
+			name: "Uncompressed Simple Font",
+			content: buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				pdfStream("", []byte("BT /F1 12 Tf 72 720 Td (Hello \\(PDF\\) world) Tj T* [(Kern)-20(ed)-400(text)] TJ ET")),
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+				"<< /Title (Press Release) /Producer (Test) >>",
+			),
+			expected: []string{"press", "release", "hello", "pdf", "world", "kerned", "text"},
+			title:    "Press Release",
+		},
+		{
+			name: "Compressed ToUnicode Font",
+			content: buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents [4 0 R] /Resources << /Font << /F2 5 0 R >> >> >>",
+				pdfStream("/Filter /FlateDecode", flate("BT /F2 10 Tf [<000100020003> -500 <00050004>] TJ ET")),
+				"<< /Type /Font /Subtype /Type0 /BaseFont /Custom /ToUnicode 6 0 R >>",
+				pdfStream("/Filter /FlateDecode", flate(cmap)),
+			),
+			expected: []string{"spa", "cb"},
+		},
+		{
+			name: "Negative Length",
+			content: buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				"<< /Length -100 >>\nstream\nBT /F1 12 Tf (Recovered text) Tj ET\nendstream",
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+			),
+			expected: []string{"recovered", "text"},
+		},
+		{
+			name: "Range Ending At Largest Code",
+			content: buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F2 5 0 R >> >> >>",
+				pdfStream("", []byte("BT /F2 10 Tf <FFFFFFFEFFFFFFFF> Tj ET")),
+				"<< /Type /Font /Subtype /Type0 /BaseFont /Custom /ToUnicode 6 0 R >>",
+				pdfStream("", []byte(`begincmap
+1 begincodespacerange <00000000> <FFFFFFFF> endcodespacerange
+1 beginbfchar
+<0000000001> <0053>
+endbfchar
+1 beginbfrange
+<FFFFFFFE> <FFFFFFFF> <0061>
+endbfrange
+endcmap`)),
+			),
+			expected: []string{"ab"},
+		},
+		{
+			name: "ASCII85 Zero Groups",
+			content: buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				pdfStream("/Filter /ASCII85Decode", a85(strings.Repeat("\x00", 400)+"BT /F1 12 Tf (After zeros) Tj ET")),
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+			),
+			expected: []string{"after", "zeros"},
+		},
+		{
+			name:     "Unterminated Hex String In Dictionary",
+			content:  []byte("%PDF-0 0 obj<<0<"),
+			expected: []string{},
+		},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := p.ParseDocument(tt.content, "application/pdf", "")
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+			if !reflect.DeepEqual(doc.Words(), tt.expected) {
+				t.Errorf("ParseDocument() words = %v, want %v", doc.Words(), tt.expected)
+			}
+			if doc.Metadata.Title != tt.title {
+				t.Errorf("ParseDocument() title = %q, want %q", doc.Metadata.Title, tt.title)
+			}
+		})
+	}
+}
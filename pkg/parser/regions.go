@@ -0,0 +1,152 @@
+// pkg/parser/regions.go
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Region identifies the part of a document a word was taken from
+type Region string
+
+const (
+	// RegionTitle is the text of the <title> element
+	RegionTitle Region = "title"
+	// RegionHeadings is the text of <h1> to <h6> elements
+	RegionHeadings Region = "headings"
+	// RegionBody is all text not belonging to another region
+	RegionBody Region = "body"
+	// RegionCaptions is the text of <figcaption> and table <caption> elements
+	RegionCaptions Region = "captions"
+	// RegionNavigation is the text of <nav> elements, and of <header>,
+	// <footer> and <aside> elements outside of the main article
+	RegionNavigation Region = "navigation"
+	// RegionAltText is image alt text and element title attributes
+	RegionAltText Region = "alt"
+	// RegionMeta is the page description from meta tags
+	RegionMeta Region = "meta"
+)
+
+// Regions lists every region in document order
+var Regions = []Region{
+	RegionMeta,
+	RegionTitle,
+	RegionHeadings,
+	RegionBody,
+	RegionCaptions,
+	RegionAltText,
+	RegionNavigation,
+}
+
+// DefaultRegions are the regions extracted when none are configured. They
+// cover every text node of the page, as plain text extraction does.
+var DefaultRegions = map[Region]bool{
+	RegionTitle:      true,
+	RegionHeadings:   true,
+	RegionBody:       true,
+	RegionCaptions:   true,
+	RegionNavigation: true,
+}
+
+// Token is a cleaned word and the region it was found in
+type Token struct {
+	Word   string
+	Region Region
+}
+
+// descriptionMetaKeys are the meta tags holding a page description
+var descriptionMetaKeys = map[string]bool{
+	"description":         true,
+	"og:description":      true,
+	"twitter:description": true,
+}
+
+// enabledRegions returns the configured regions or DefaultRegions
+func (p *Parser) enabledRegions() map[Region]bool {
+	if len(p.config.Regions) == 0 {
+		return DefaultRegions
+	}
+	return p.config.Regions
+}
+
+// extractTokens walks the document and returns the words of the enabled
+// regions, skipping script and style elements
+func (p *Parser) extractTokens(doc *html.Node, enabled map[Region]bool) []Token {
+	var tokens []Token
+	appendTokens := func(text string, region Region) {
+		if enabled[region] {
+			tokens = p.appendTokens(tokens, text, region)
+		}
+	}
+
+	var visit func(n *html.Node, region Region, inArticle bool)
+	visit = func(n *html.Node, region Region, inArticle bool) {
+		switch n.Type {
+		case html.TextNode:
+			appendTokens(n.Data, region)
+			return
+		case html.ElementNode:
+			// if its script or style ignore
+			if n.Data == "script" || n.Data == "style" {
+				return
+			}
+			attributeText(n.Data, n.Attr, appendTokens)
+			region, inArticle = childRegion(n.Data, region, inArticle)
+		}
+
+		// Recursively process child nodes
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c, region, inArticle)
+		}
+	}
+
+	visit(doc, RegionBody, false)
+	return tokens
+}
+
+// attributeText passes the text an element carries in its attributes, such
+// as meta descriptions and image alt text, to add along with its region
+func attributeText(tag string, attrs []html.Attribute, add func(text string, region Region)) {
+	switch tag {
+	case "meta":
+		key := attrValue(attrs, "name")
+		if key == "" {
+			key = attrValue(attrs, "property")
+		}
+		if descriptionMetaKeys[strings.ToLower(key)] {
+			add(attrValue(attrs, "content"), RegionMeta)
+		}
+	case "img", "area":
+		add(attrValue(attrs, "alt"), RegionAltText)
+	}
+	add(attrValue(attrs, "title"), RegionAltText)
+}
+
+// childRegion returns the region of the text inside an element found in
+// region, and whether that text is part of the main article
+func childRegion(tag string, region Region, inArticle bool) (Region, bool) {
+	if tag == "article" || tag == "main" {
+		inArticle = true
+	}
+
+	// Navigation wins over any nested region
+	if region == RegionNavigation {
+		return region, inArticle
+	}
+	switch tag {
+	case "nav":
+		region = RegionNavigation
+	case "header", "footer", "aside":
+		if !inArticle {
+			region = RegionNavigation
+		}
+	case "title":
+		region = RegionTitle
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		region = RegionHeadings
+	case "figcaption", "caption":
+		region = RegionCaptions
+	}
+	return region, inArticle
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractRegions(t *testing.T) {
+	content := `<html><head><title>Page Title</title><meta name="description" content="Short summary"></head>
+<body><header><nav><a href="/" title="Home page">Home</a></nav></header>
+<article><header><h1>Headline</h1></header><p>Body text</p>
+<figure><img src="a.jpg" alt="Photo alt"><figcaption>Photo caption</figcaption></figure></article>
+<footer>Subscribe</footer></body></html>`
+
+	tests := []struct {
+		name     string
+		regions  map[Region]bool
+		expected []Token
+	}{
+		{
+			name:    "Default Regions",
+			regions: nil,
+			expected: []Token{
+				{Word: "page", Region: RegionTitle},
+				{Word: "title", Region: RegionTitle},
+				{Word: "home", Region: RegionNavigation},
+				{Word: "headline", Region: RegionHeadings},
+				{Word: "body", Region: RegionBody},
+				{Word: "text", Region: RegionBody},
+				{Word: "photo", Region: RegionCaptions},
+				{Word: "caption", Region: RegionCaptions},
+				{Word: "subscribe", Region: RegionNavigation},
+			},
+		},
+		{
+			name:    "Meta And Alt Text Without Navigation",
+			regions: map[Region]bool{RegionMeta: true, RegionAltText: true, RegionBody: true},
+			expected: []Token{
+				{Word: "short", Region: RegionMeta},
+				{Word: "summary", Region: RegionMeta},
+				{Word: "home", Region: RegionAltText},
+				{Word: "page", Region: RegionAltText},
+				{Word: "body", Region: RegionBody},
+				{Word: "text", Region: RegionBody},
+				{Word: "photo", Region: RegionAltText},
+				{Word: "alt", Region: RegionAltText},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithConfig(ParserConfig{Regions: tt.regions})
+			doc, err := p.ParseDocument([]byte(content), "text/html", "")
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+			if !reflect.DeepEqual(doc.Tokens, tt.expected) {
+				t.Errorf("ParseDocument() tokens = %v, want %v", doc.Tokens, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// pkg/parser/stream.go
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+// sniffLen is the number of bytes peeked from a stream to detect its media
+// type and character encoding
+const sniffLen = 1024
+
+// voidElements never have an end tag, so they are not pushed on the
+// element stack while streaming
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// ParseStream extracts words from a body as it is read and passes each token
+// to emit, without holding the whole body or its words in memory. HTML is
+// tokenized incrementally; other supported types are read fully and parsed
+// with ParseDocument. Bodies of unsupported types are not read past their
+// first bytes. The returned document has no Tokens. Errors returned by emit
+// stop parsing and are returned as is.
+func (p *Parser) ParseStream(r io.Reader, contentType, pageURL string, emit func(Token) error) (*Document, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("error reading content: %w", err)
+	}
+
+	mediaType := DetectMediaType(head, contentType, pageURL)
+	if _, ok := contentHandlers[mediaType]; !ok {
+		// Don't download bodies that can't be parsed anyway
+		return nil, &UnsupportedTypeError{MediaType: mediaType}
+	}
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		content, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading content: %w", err)
+		}
+		doc, err := p.ParseDocument(content, contentType, pageURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range doc.Tokens {
+			if err := emit(token); err != nil {
+				return nil, err
+			}
+		}
+		doc.Tokens = nil
+		return doc, nil
+	}
+
+	doc, err := p.streamHTML(br, head, contentType, pageURL, emit)
+	if err != nil {
+		return nil, err
+	}
+	doc.ContentType = mediaType
+	return doc, nil
+}
+
+// streamElement is an open element on the streaming tokenizer's stack
+type streamElement struct {
+	tag       string
+	region    Region
+	inArticle bool
+	jsonLD    bool
+}
+
+// streamHTML tokenizes HTML from r, tracking open elements to assign text
+// to the same regions extractTokens does. With StrategyJSONLD, body and
+// navigation words are held back until the page is known to have no JSON-LD
+// articleBody, since it usually but not always precedes the page text.
+func (p *Parser) streamHTML(r io.Reader, head []byte, contentType, pageURL string, emit func(Token) error) (*Document, error) {
+	enc, encoding, _ := charset.DetermineEncoding(head, contentType)
+	if encoding != "utf-8" {
+		r = transform.NewReader(r, enc.NewDecoder())
+	}
+
+	enabled := p.enabledRegions()
+	strategy := p.strategyFor(pageURL)
+	meta := newMetadataCollector()
+
+	var articleBody string
+	var held []Token
+	var emitErr error
+	emitText := func(text string, region Region) {
+		for _, word := range strings.Fields(text) {
+			if word = cleanWord(p.Normalize(word)); word != "" {
+				if emitErr = emit(Token{Word: word, Region: region}); emitErr != nil {
+					return
+				}
+			}
+		}
+	}
+	add := func(text string, region Region) {
+		if emitErr != nil || !enabled[region] {
+			return
+		}
+		if strategy == StrategyJSONLD && (region == RegionBody || region == RegionNavigation) {
+			if articleBody == "" {
+				held = p.appendTokens(held, text, region)
+			}
+			return
+		}
+		emitText(text, region)
+	}
+
+	stack := []streamElement{{region: RegionBody}}
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, fmt.Errorf("error tokenizing HTML: %w", err)
+			}
+			break
+		}
+
+		top := stack[len(stack)-1]
+		switch tt {
+		case html.TextToken:
+			switch {
+			case top.jsonLD:
+				meta.jsonLD(z.Text())
+				if strategy == StrategyJSONLD && articleBody == "" && meta.article != nil {
+					if articleBody = jsonString(meta.article["articleBody"]); articleBody != "" {
+						held = nil
+					}
+				}
+			case top.tag == "script" || top.tag == "style":
+			default:
+				text := string(z.Text())
+				if top.tag == "title" {
+					meta.titleText(text)
+				}
+				add(text, top.region)
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			meta.element(token.Data, token.Attr)
+			if top.tag == "script" || top.tag == "style" {
+				break
+			}
+			attributeText(token.Data, token.Attr, add)
+			if tt == html.SelfClosingTagToken || voidElements[token.Data] {
+				break
+			}
+			region, inArticle := childRegion(token.Data, top.region, top.inArticle)
+			stack = append(stack, streamElement{
+				tag:       token.Data,
+				region:    region,
+				inArticle: inArticle,
+				jsonLD:    isJSONLDScript(token.Data, token.Attr),
+			})
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == string(name) {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+
+		if emitErr != nil {
+			return nil, emitErr
+		}
+	}
+
+	// Release the held back words, or replace them with the articleBody
+	if articleBody != "" {
+		if enabled[RegionBody] {
+			emitText(articleBody, RegionBody)
+		}
+	} else {
+		strategy = StrategyDOM
+		for _, token := range held {
+			if emitErr = emit(token); emitErr != nil {
+				break
+			}
+		}
+	}
+	if emitErr != nil {
+		return nil, emitErr
+	}
+
+	return &Document{
+		Encoding: encoding,
+		Strategy: strategy,
+		Metadata: meta.metadata(),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseStreamMatchesParseDocument(t *testing.T) {
+	config := ParserConfig{
+		DomainStrategies: map[string]string{"engadget.com": StrategyJSONLD},
+		Regions: map[Region]bool{
+			RegionTitle: true, RegionHeadings: true, RegionBody: true, RegionCaptions: true,
+			RegionNavigation: true, RegionAltText: true, RegionMeta: true,
+		},
+	}
+	jsonLD := `<script type="application/ld+json">{"@type":"NewsArticle","headline":"Launch Day","articleBody":"Clean article body"}</script>`
+
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		pageURL     string
+	}{
+		{
+			name: "Regions",
+			content: `<html><head><title>Page Title</title><meta name="description" content="Short summary">
+<style>p { color: red }</style></head><body><header>Site Header</header><nav><a href="/">Home <b>Link</b></a></nav>
+<article><header><h1>Main Heading</h1></header><p>First paragraph<br>continues &amp; ends</p>
+<figure><img src="a.png" alt="Chart image"/><figcaption>Figure caption</figcaption></figure>
+<script>var ignored = "script text";</script></article><footer>Copyright notice</footer></body></html>`,
+			contentType: "text/html",
+			pageURL:     "https://example.com/story",
+		},
+		{
+			name:        "Windows-1252",
+			content:     "<html><head><meta charset=\"windows-1252\"></head><body><p>caf\xe9 cr\xe8me</p></body></html>",
+			contentType: "text/html",
+			pageURL:     "https://example.com/story",
+		},
+		{
+			name:        "JSON-LD Before Body",
+			content:     `<html><head>` + jsonLD + `</head><body><nav>Subscribe now</nav><h2>Section</h2><p>Page text</p></body></html>`,
+			contentType: "text/html",
+			pageURL:     "https://www.engadget.com/story",
+		},
+		{
+			name:        "JSON-LD After Body",
+			content:     `<html><body><nav>Subscribe now</nav><p>Page text</p>` + jsonLD + `</body></html>`,
+			contentType: "text/html",
+			pageURL:     "https://www.engadget.com/story",
+		},
+		{
+			name:        "JSON-LD Missing",
+			content:     `<html><body><nav>Subscribe now</nav><p>Page text</p></body></html>`,
+			contentType: "text/html",
+			pageURL:     "https://www.engadget.com/story",
+		},
+		{
+			name:        "Markdown",
+			content:     "# Release Notes\n\nThe product ships *today*.",
+			contentType: "text/markdown",
+			pageURL:     "https://example.com/notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithConfig(config)
+			want, err := p.ParseDocument([]byte(tt.content), tt.contentType, tt.pageURL)
+			if err != nil {
+				t.Fatalf("ParseDocument() error = %v", err)
+			}
+
+			var tokens []Token
+			got, err := p.ParseStream(strings.NewReader(tt.content), tt.contentType, tt.pageURL, func(token Token) error {
+				tokens = append(tokens, token)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ParseStream() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(sortedTokens(tokens), sortedTokens(want.Tokens)) {
+				t.Errorf("ParseStream() tokens = %v, want %v", tokens, want.Tokens)
+			}
+			want.Tokens = nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseStream() document = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseStreamErrors(t *testing.T) {
+	p := New()
+
+	_, err := p.ParseStream(strings.NewReader("\x89PNG\r\n\x1a\n"), "image/png", "https://example.com/logo.png", func(Token) error {
+		return nil
+	})
+	var unsupported *UnsupportedTypeError
+	if !errors.As(err, &unsupported) || unsupported.MediaType != "image/png" {
+		t.Errorf("ParseStream() error = %v, want unsupported image/png", err)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	_, err = p.ParseStream(strings.NewReader("<p>one two three</p>"), "text/html", "", func(Token) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Errorf("ParseStream() error = %v after %d tokens, want stop after 1", err, count)
+	}
+}
+
+// sortedTokens returns a sorted copy of tokens for order-insensitive comparison
+func sortedTokens(tokens []Token) []Token {
+	sorted := append([]Token(nil), tokens...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Region != sorted[j].Region {
+			return sorted[i].Region < sorted[j].Region
+		}
+		return sorted[i].Word < sorted[j].Word
+	})
+	return sorted
+}
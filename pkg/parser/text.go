@@ -0,0 +1,140 @@
+// pkg/parser/text.go
+package parser
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink      = regexp.MustCompile(`\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`)
+	markdownCode      = regexp.MustCompile("`[^`]*`")
+	markdownAutolink  = regexp.MustCompile(`<(?:https?|ftp|mailto):[^>]*>`)
+	markdownHTMLTag   = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	markdownReference = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s`)
+	markdownHeading   = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownUnderline = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+)
+
+// appendTokens appends the words of text to tokens as tokens of region
+func (p *Parser) appendTokens(tokens []Token, text string, region Region) []Token {
+	for _, word := range p.appendWords(nil, text) {
+		tokens = append(tokens, Token{Word: word, Region: region})
+	}
+	return tokens
+}
+
+// parseText extracts the words of a plain text body
+func (p *Parser) parseText(content []byte, contentType, pageURL string) (*Document, error) {
+	decoded, encoding, err := DecodeToUTF8(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Encoding: encoding}
+	if p.enabledRegions()[RegionBody] {
+		doc.Tokens = p.appendTokens(nil, string(decoded), RegionBody)
+	}
+	return doc, nil
+}
+
+// parseMarkdown extracts the words of a Markdown body. Headings, image alt
+// text and front matter titles are assigned to their regions, while code,
+// link targets and raw HTML tags are skipped.
+func (p *Parser) parseMarkdown(content []byte, contentType, pageURL string) (*Document, error) {
+	decoded, encoding, err := DecodeToUTF8(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := p.enabledRegions()
+	doc := &Document{Encoding: encoding}
+	add := func(text string, region Region) {
+		if enabled[region] {
+			doc.Tokens = p.appendTokens(doc.Tokens, text, region)
+		}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(decoded)))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	start := 0
+	// Front matter: only its title is of interest
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			line := strings.TrimSpace(lines[i])
+			if line == "---" || line == "..." {
+				start = i + 1
+				break
+			}
+			if value, ok := strings.CutPrefix(line, "title:"); ok {
+				doc.Metadata.Title = strings.Trim(strings.TrimSpace(value), `"'`)
+				add(doc.Metadata.Title, RegionTitle)
+			}
+		}
+	}
+
+	inFence := ""
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Skip fenced code blocks
+		if inFence != "" {
+			if strings.HasPrefix(trimmed, inFence) {
+				inFence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = trimmed[:3]
+			continue
+		}
+
+		if markdownReference.MatchString(line) {
+			continue
+		}
+
+		region := RegionBody
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			region, line = RegionHeadings, m[2]
+			if len(m[1]) == 1 && doc.Metadata.Title == "" {
+				doc.Metadata.Title = markdownInlineText(line)
+			}
+		} else if trimmed != "" && i+1 < len(lines) && markdownUnderline.MatchString(lines[i+1]) {
+			// Setext heading, the underline is skipped with it
+			region = RegionHeadings
+			if strings.HasPrefix(strings.TrimSpace(lines[i+1]), "=") && doc.Metadata.Title == "" {
+				doc.Metadata.Title = markdownInlineText(line)
+			}
+			i++
+		}
+
+		for _, m := range markdownImage.FindAllStringSubmatch(line, -1) {
+			add(m[1], RegionAltText)
+		}
+		add(markdownInlineText(line), region)
+	}
+
+	return doc, nil
+}
+
+// markdownInlineText strips inline Markdown syntax that does not contribute
+// words: images, link targets, code spans, autolinks and HTML tags
+func markdownInlineText(line string) string {
+	line = markdownImage.ReplaceAllString(line, " ")
+	line = markdownLink.ReplaceAllString(line, "$1")
+	line = markdownCode.ReplaceAllString(line, " ")
+	line = markdownAutolink.ReplaceAllString(line, " ")
+	line = markdownHTMLTag.ReplaceAllString(line, " ")
+	return strings.TrimSpace(line)
+}
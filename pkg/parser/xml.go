@@ -0,0 +1,62 @@
+// pkg/parser/xml.go
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// parseXML extracts the character data of a generic XML body, such as a
+// feed or a transcript. The first <title> element is used as the title.
+func (p *Parser) parseXML(content []byte, contentType, pageURL string) (*Document, error) {
+	doc := &Document{Encoding: "utf-8"}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		doc.Encoding = strings.ToLower(label)
+		return charset.NewReaderLabel(label, input)
+	}
+
+	enabled := p.enabledRegions()
+	var path []string
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path = append(path, strings.ToLower(t.Name.Local))
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		case xml.CharData:
+			region := RegionBody
+			if len(path) > 0 && path[len(path)-1] == "title" {
+				region = RegionTitle
+				if doc.Metadata.Title == "" {
+					doc.Metadata.Title = strings.TrimSpace(string(t))
+				}
+			}
+			if enabled[region] {
+				doc.Tokens = p.appendTokens(doc.Tokens, string(t), region)
+			}
+		}
+	}
+
+	return doc, nil
+}
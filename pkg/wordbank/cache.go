@@ -0,0 +1,145 @@
+package wordbank
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps downloaded word lists on disk, keyed by URL. Each entry stores
+// the uncompressed content next to a JSON record of its SHA-256 and the
+// validators needed to revalidate it. It also keeps the words parsed from word
+// lists, so that unchanged lists are not parsed again.
+type Cache struct {
+	dir string
+}
+
+// cacheRecord is the JSON record stored next to a cached word list
+type cacheRecord struct {
+	URL          string    `json:"url"`
+	SHA256       string    `json:"sha256"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// NewCache creates a cache in dir, which is created on first use
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// path returns the path of the entry of key, a URL or a parsed list key,
+// without extension
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Load returns the cached copy of url, failing if there is none or its
+// content no longer matches the recorded SHA-256
+func (c *Cache) Load(url string) (*List, error) {
+	path := c.path(url)
+	data, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache record: %w", err)
+	}
+	var record cacheRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("error decoding cache record: %w", err)
+	}
+
+	content, err := os.ReadFile(path + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("error reading cached word list: %w", err)
+	}
+	list := newList(content, OriginCache)
+	if list.SHA256 != record.SHA256 {
+		return nil, fmt.Errorf("cached word list for %s is corrupt: SHA-256 %s, expected %s", url, list.SHA256, record.SHA256)
+	}
+	list.ETag = record.ETag
+	list.LastModified = record.LastModified
+	return list, nil
+}
+
+// Store saves list as the cached copy of url. The content is written before
+// the record so that an interrupted store fails the integrity check.
+func (c *Cache) Store(url string, list *List) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	record, err := json.MarshalIndent(cacheRecord{
+		URL:          url,
+		SHA256:       list.SHA256,
+		ETag:         list.ETag,
+		LastModified: list.LastModified,
+		FetchedAt:    time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cache record: %w", err)
+	}
+
+	path := c.path(url)
+	if err := writeFileAtomic(path+".txt", list.Content); err != nil {
+		return err
+	}
+	return writeFileAtomic(path+".json", record)
+}
+
+// LoadEntries returns the words cached under key by StoreEntries
+func (c *Cache) LoadEntries(key string) ([]Entry, error) {
+	file, err := os.Open(c.path(key) + ".gob")
+	if err != nil {
+		return nil, fmt.Errorf("error reading cached words: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("error decoding cached words: %w", err)
+	}
+	return entries, nil
+}
+
+// StoreEntries caches the words parsed from a word list under key, which must
+// identify both the list's content and how it was parsed
+func (c *Cache) StoreEntries(key string, entries []Entry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		return fmt.Errorf("error encoding cached words: %w", err)
+	}
+	return writeFileAtomic(c.path(key)+".gob", buf.Bytes())
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path,
+// so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package wordbank
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReaderCache(t *testing.T) {
+	content := "alpha\nbeta"
+	down := false
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if down {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	cache := NewCache(t.TempDir())
+	r := NewReader(5*time.Second, "")
+	r.SetCache(cache)
+	ctx := context.Background()
+
+	steps := []struct {
+		name       string
+		setup      func()
+		wantOrigin string
+	}{
+		{"First Read Downloads", func() {}, OriginNetwork},
+		{"Unchanged List Revalidates", func() {}, OriginCache},
+		{"Failed Download Falls Back", func() { down = true }, OriginStaleCache},
+		{"Corrupt Cache Is Downloaded Again", func() {
+			down = false
+			os.WriteFile(cache.path(server.URL)+".txt", []byte("tampered"), 0644)
+		}, OriginNetwork},
+	}
+
+	for _, step := range steps {
+		step.setup()
+		list, err := r.Read(ctx, server.URL)
+		if err != nil {
+			t.Fatalf("%s: Read() error = %v", step.name, err)
+		}
+		if list.Origin != step.wantOrigin || string(list.Content) != content {
+			t.Errorf("%s: Read() = %q from %s, want %q from %s", step.name, list.Content, list.Origin, content, step.wantOrigin)
+		}
+		if list.SHA256 != newList([]byte(content), "").SHA256 {
+			t.Errorf("%s: SHA256 = %s, want hash of content", step.name, list.SHA256)
+		}
+	}
+	if requests != len(steps) {
+		t.Errorf("Expected %d requests, got %d", len(steps), requests)
+	}
+
+	// Without a cached copy a failed download is an error
+	down = true
+	if _, err := NewReader(5*time.Second, "").Read(ctx, server.URL); err == nil {
+		t.Error("Expected error without a cached copy")
+	}
+}
+
+func TestCacheEntries(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	entries := []Entry{
+		{Word: "alpha"},
+		{Word: "beta", Attributes: Attributes{Frequency: 2.5, PartOfSpeech: "noun", Category: "greek"}},
+	}
+
+	if _, err := cache.LoadEntries("key"); err == nil {
+		t.Error("Expected error loading missing entries")
+	}
+	if err := cache.StoreEntries("key", entries); err != nil {
+		t.Fatalf("StoreEntries() error = %v", err)
+	}
+	got, err := cache.LoadEntries("key")
+	if err != nil {
+		t.Fatalf("LoadEntries() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("LoadEntries() = %v, want %v", got, entries)
+	}
+}
+
+func TestReaderReportsCacheErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("alpha"))
+	}))
+	defer server.Close()
+
+	// A file where the cache directory should be makes every store fail
+	dir := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	r := NewReader(5*time.Second, "")
+	r.SetCache(NewCache(dir))
+
+	list, err := r.Read(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if list.CacheError == "" || string(list.Content) != "alpha" {
+		t.Errorf("Expected the downloaded list with a cache error, got %q (cache error %q)", list.Content, list.CacheError)
+	}
+}
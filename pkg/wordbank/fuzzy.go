@@ -0,0 +1,162 @@
+package wordbank
+
+import (
+	"hash/maphash"
+	"slices"
+)
+
+// prefixLength is how many leading letters of a word the fuzzy index stores
+// deletions of. Longer words are still compared in full, but limiting the
+// deletions keeps the index size linear in the number of words.
+const prefixLength = 7
+
+// FuzzyIndex finds the words of a snapshot within a bounded edit distance of
+// a word using symmetric deletion: two words at most n edits apart have a
+// common form left after deleting at most n letters from each, so the index
+// maps every such deletion of each word back to the word, and a lookup
+// verifies the words sharing a deletion with the query. Distances count
+// insertions, deletions, substitutions and transpositions of adjacent
+// letters, so "teh" is one edit away from "the".
+type FuzzyIndex struct {
+	snapshot    *Snapshot
+	maxDistance int
+	// entries holds the 32-bit hash of each deletion in its high bits and the
+	// index of the word it was made from in its low bits, sorted. Hash
+	// collisions only add candidates that fail verification.
+	entries []uint64
+	seed    maphash.Seed
+}
+
+// NewFuzzyIndex builds a fuzzy index of the words of s finding words up to
+// maxDistance edits away. Each extra edit allowed multiplies the size of the
+// index: about 8 entries per word for one edit and 30 for two.
+func NewFuzzyIndex(s *Snapshot, maxDistance int) *FuzzyIndex {
+	f := &FuzzyIndex{snapshot: s, maxDistance: maxDistance, seed: maphash.MakeSeed()}
+	for i := 0; i < s.Len(); i++ {
+		for deletion := range deletions(s.word(i), maxDistance) {
+			f.entries = append(f.entries, f.hash(deletion)<<32|uint64(i))
+		}
+	}
+	slices.Sort(f.entries)
+	return f
+}
+
+func (f *FuzzyIndex) hash(s string) uint64 {
+	return maphash.String(f.seed, s) >> 32
+}
+
+// deletions returns the forms of the first prefixLength letters of word left
+// after deleting up to maxDistance of them, including none
+func deletions(word string, maxDistance int) map[string]struct{} {
+	key := []rune(word)
+	if len(key) > prefixLength {
+		key = key[:prefixLength]
+	}
+	result := map[string]struct{}{string(key): {}}
+	level := [][]rune{key}
+	for d := 0; d < maxDistance; d++ {
+		var next [][]rune
+		for _, runes := range level {
+			for i := range runes {
+				deleted := make([]rune, 0, len(runes)-1)
+				deleted = append(append(deleted, runes[:i]...), runes[i+1:]...)
+				if _, seen := result[string(deleted)]; !seen {
+					result[string(deleted)] = struct{}{}
+					next = append(next, deleted)
+				}
+			}
+		}
+		level = next
+	}
+	return result
+}
+
+// Closest returns the word nearest to word at most maxDistance edits away,
+// and its distance, maxDistance being capped at the index's. Ties go to the
+// word with the highest frequency in the word bank, then to the first
+// alphabetically. Like Contains, it expects an already normalized word.
+func (f *FuzzyIndex) Closest(word string, maxDistance int) (string, int, bool) {
+	maxDistance = min(maxDistance, f.maxDistance)
+	query := []rune(word)
+	best, bestDistance := -1, maxDistance+1
+	checked := make(map[uint32]struct{})
+	for deletion := range deletions(word, maxDistance) {
+		hash := f.hash(deletion)
+		i, _ := slices.BinarySearch(f.entries, hash<<32)
+		for ; i < len(f.entries) && f.entries[i]>>32 == hash; i++ {
+			index := uint32(f.entries[i])
+			if _, ok := checked[index]; ok {
+				continue
+			}
+			checked[index] = struct{}{}
+
+			candidate := []rune(f.snapshot.word(int(index)))
+			if abs(len(candidate)-len(query)) > maxDistance {
+				continue
+			}
+			d := editDistance(query, candidate)
+			if d > maxDistance {
+				continue
+			}
+			if d < bestDistance || d == bestDistance && f.better(int(index), best) {
+				best, bestDistance = int(index), d
+			}
+		}
+	}
+
+	if best < 0 {
+		return "", 0, false
+	}
+	return f.snapshot.word(best), bestDistance, true
+}
+
+// better reports whether the word at index i wins a tie against the word at
+// index j, which is -1 when there is none yet
+func (f *FuzzyIndex) better(i, j int) bool {
+	if j < 0 {
+		return true
+	}
+	fi, _ := f.snapshot.Attributes(f.snapshot.word(i))
+	fj, _ := f.snapshot.Attributes(f.snapshot.word(j))
+	if fi.Frequency != fj.Frequency {
+		return fi.Frequency > fj.Frequency
+	}
+	// Snapshot words are sorted, so a lower index is first alphabetically
+	return i < j
+}
+
+// editDistance returns the optimal string alignment distance between a and b:
+// the number of insertions, deletions, substitutions and adjacent
+// transpositions turning a into b, no substring being edited twice
+func editDistance(a, b []rune) int {
+	// Three rows of the dynamic programming matrix: two back, previous, current
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package wordbank
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"the", "the", 0},
+		{"teh", "the", 1},
+		{"recieve", "receive", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"café", "cafe", 1},
+		{"ca", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestFuzzyIndexClosest(t *testing.T) {
+	wb := New()
+	for _, word := range []string{"the", "receive", "believe", "cat", "cut", "coat"} {
+		wb.Add(word)
+	}
+	wb.AddEntry("cot", Attributes{Frequency: 5})
+	f := NewFuzzyIndex(wb.Freeze(), 2)
+
+	tests := []struct {
+		word         string
+		maxDistance  int
+		expected     string
+		wantDistance int
+		wantOK       bool
+	}{
+		{"teh", 1, "the", 1, true},
+		{"recieve", 1, "receive", 1, true},
+		{"beleive", 2, "believe", 1, true},
+		{"receive", 1, "receive", 0, true},
+		{"cxt", 1, "cot", 1, true},  // most frequent of cat, cot and cut
+		{"cbat", 1, "cat", 1, true}, // first of cat and coat
+		{"dog", 1, "", 0, false},
+		{"recievd", 1, "", 0, false},
+		{"recievd", 2, "receive", 2, true},
+	}
+
+	for _, tt := range tests {
+		got, distance, ok := f.Closest(tt.word, tt.maxDistance)
+		if got != tt.expected || distance != tt.wantDistance || ok != tt.wantOK {
+			t.Errorf("Closest(%q, %d) = %q, %d, %v, want %q, %d, %v",
+				tt.word, tt.maxDistance, got, distance, ok, tt.expected, tt.wantDistance, tt.wantOK)
+		}
+	}
+}
+
+func TestFuzzyIndexMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomWord := func() string {
+		word := make([]rune, 2+rng.Intn(10))
+		for i := range word {
+			word[i] = rune('a' + rng.Intn(5))
+		}
+		return string(word)
+	}
+
+	wb := New()
+	for i := 0; i < 500; i++ {
+		wb.Add(randomWord())
+	}
+	s := wb.Freeze()
+	f := NewFuzzyIndex(s, 2)
+
+	for i := 0; i < 500; i++ {
+		query := randomWord()
+		best, bestDistance := "", 3
+		for word := range s.All() {
+			if d := editDistance([]rune(query), []rune(word)); d < bestDistance {
+				best, bestDistance = word, d
+			}
+		}
+
+		got, distance, ok := f.Closest(query, 2)
+		if ok != (best != "") || got != best || ok && distance != bestDistance {
+			t.Fatalf("Closest(%q) = %q, %d, %v, want %q at distance %d", query, got, distance, ok, best, bestDistance)
+		}
+	}
+}
+
+func BenchmarkFuzzyIndexClosest(b *testing.B) {
+	wb, words := randomWordBank(benchmarkWords)
+	f := NewFuzzyIndex(wb.Freeze(), 1)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		// Drop the last letter of the word number suffix to force a fuzzy match
+		word := words[i%benchmarkWords]
+		f.Closest(word[:len(word)-1], 1)
+	}
+}
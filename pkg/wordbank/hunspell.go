@@ -0,0 +1,438 @@
+package wordbank
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Hunspell dictionaries are a .dic file listing stems with affix flags and an
+// .aff file defining the prefix and suffix rules those flags stand for.
+// ExpandHunspell generates every form the rules allow up front, so a word bank
+// built from it answers lookups by plain set membership. Compounding rules are
+// not supported.
+
+// affixRule is a single PFX or SFX line
+type affixRule struct {
+	strip string
+	add   string
+	cond  condition
+	// flags are the continuation classes the affixed form may take
+	flags []string
+}
+
+// affixClass is the set of rules sharing a flag
+type affixClass struct {
+	prefix bool
+	// cross allows combining the class with affixes of the other kind
+	cross bool
+	rules []affixRule
+}
+
+// affixFile is a parsed .aff file
+type affixFile struct {
+	flagType       string
+	aliases        [][]string
+	classes        map[string]*affixClass
+	needAffix      string
+	forbidden      string
+	onlyInCompound string
+	ignore         string
+}
+
+// ExpandHunspell returns the stems of dic with all their affixed forms, using
+// the rules of aff. Both files are decoded from the encoding named by the
+// SET directive of aff, UTF-8 if missing.
+func ExpandHunspell(dic, aff []byte) ([]string, error) {
+	enc := affixEncoding(aff)
+	aff, err := decodeHunspell(aff, enc)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding affix file: %w", err)
+	}
+	dic, err = decodeHunspell(dic, enc)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding dictionary: %w", err)
+	}
+
+	a, err := parseAffixFile(string(aff))
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	emit := func(word string) { words = append(words, word) }
+	for i, line := range strings.Split(string(dic), "\n") {
+		line = strings.TrimSpace(line)
+		// The first line is the approximate number of stems
+		if line == "" || strings.HasPrefix(line, "#") || i == 0 && isNumber(line) {
+			continue
+		}
+		stem, flags := splitDicLine(line)
+		a.expand(a.stripIgnored(stem), a.parseFlags(flags), emit)
+	}
+	return words, nil
+}
+
+// affixEncoding returns the encoding named by the SET directive of aff
+func affixEncoding(aff []byte) string {
+	for _, line := range bytes.Split(aff, []byte("\n")) {
+		fields := strings.Fields(string(line))
+		if len(fields) >= 2 && fields[0] == "SET" {
+			return fields[1]
+		}
+	}
+	return "UTF-8"
+}
+
+// decodeHunspell converts content in enc to UTF-8, dropping any byte order mark
+func decodeHunspell(content []byte, enc string) ([]byte, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if strings.EqualFold(enc, "UTF-8") {
+		return content, nil
+	}
+	e, _ := charset.Lookup(enc)
+	if e == nil {
+		return nil, fmt.Errorf("unsupported encoding %q", enc)
+	}
+	return e.NewDecoder().Bytes(content)
+}
+
+// splitDicLine splits a .dic line into its stem and flags, dropping any
+// morphological fields. A slash in the stem is escaped as \/.
+func splitDicLine(line string) (string, string) {
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		line = line[:i]
+	}
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '/':
+			return strings.ReplaceAll(line[:i], `\/`, "/"), line[i+1:]
+		}
+	}
+	return strings.ReplaceAll(line, `\/`, "/"), ""
+}
+
+// parseAffixFile parses the directives of an .aff file relevant to expansion
+func parseAffixFile(content string) (*affixFile, error) {
+	a := &affixFile{classes: make(map[string]*affixClass)}
+	aliasCount := false
+	for n, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG", "NEEDAFFIX", "FORBIDDENWORD", "ONLYINCOMPOUND", "IGNORE":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("affix file line %d: missing %s value", n+1, fields[0])
+			}
+		}
+		switch fields[0] {
+		case "FLAG":
+			a.flagType = fields[1]
+		case "AF":
+			// The first AF line is the number of aliases
+			if !aliasCount {
+				aliasCount = true
+			} else if len(fields) >= 2 {
+				a.aliases = append(a.aliases, a.splitFlags(fields[1]))
+			}
+		case "NEEDAFFIX":
+			a.needAffix = fields[1]
+		case "FORBIDDENWORD":
+			a.forbidden = fields[1]
+		case "ONLYINCOMPOUND":
+			a.onlyInCompound = fields[1]
+		case "IGNORE":
+			a.ignore = fields[1]
+		case "PFX", "SFX":
+			if err := a.parseAffixLine(fields); err != nil {
+				return nil, fmt.Errorf("affix file line %d: %w", n+1, err)
+			}
+		}
+	}
+	return a, nil
+}
+
+// parseAffixLine parses a PFX or SFX line, either the header defining a
+// class or one of its rules
+func (a *affixFile) parseAffixLine(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("malformed %s line", fields[0])
+	}
+	flag := fields[1]
+	class, ok := a.classes[flag]
+	if !ok {
+		a.classes[flag] = &affixClass{prefix: fields[0] == "PFX", cross: fields[2] == "Y"}
+		return nil
+	}
+
+	rule := affixRule{strip: fields[2]}
+	if rule.strip == "0" {
+		rule.strip = ""
+	}
+	add, flags, _ := strings.Cut(fields[3], "/")
+	if add != "0" {
+		rule.add = add
+	}
+	if flags != "" {
+		rule.flags = a.parseFlags(flags)
+	}
+	rule.strip, rule.add = a.stripIgnored(rule.strip), a.stripIgnored(rule.add)
+
+	cond := "."
+	if len(fields) >= 5 {
+		cond = fields[4]
+	}
+	var err error
+	if rule.cond, err = parseCondition(cond); err != nil {
+		return err
+	}
+	class.rules = append(class.rules, rule)
+	return nil
+}
+
+// parseFlags returns the flags of a .dic entry or affix rule, resolving
+// numeric aliases when the affix file defines AF
+func (a *affixFile) parseFlags(s string) []string {
+	if len(a.aliases) > 0 && isNumber(s) {
+		if i, _ := strconv.Atoi(s); i >= 1 && i <= len(a.aliases) {
+			return a.aliases[i-1]
+		}
+		return nil
+	}
+	return a.splitFlags(s)
+}
+
+// splitFlags splits s according to the FLAG type: one character per flag by
+// default, two with long, and comma-separated numbers with num
+func (a *affixFile) splitFlags(s string) []string {
+	switch a.flagType {
+	case "num":
+		return strings.Split(s, ",")
+	case "long":
+		runes := []rune(s)
+		var flags []string
+		for i := 0; i+1 < len(runes); i += 2 {
+			flags = append(flags, string(runes[i:i+2]))
+		}
+		return flags
+	}
+	var flags []string
+	for _, r := range s {
+		flags = append(flags, string(r))
+	}
+	return flags
+}
+
+// stripIgnored removes the characters listed by IGNORE from s
+func (a *affixFile) stripIgnored(s string) string {
+	if a.ignore == "" {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(a.ignore, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// affixedForm is a stem with suffixes applied, and the prefix classes it may
+// still take
+type affixedForm struct {
+	word     string
+	prefixes []string
+}
+
+// expand emits stem, unless its flags forbid it standing alone, and every
+// form its affix flags allow: one or two suffixes, then a prefix
+func (a *affixFile) expand(stem string, flags []string, emit func(string)) {
+	if stem == "" || hasFlag(flags, a.forbidden) {
+		return
+	}
+	if !hasFlag(flags, a.needAffix) && !hasFlag(flags, a.onlyInCompound) {
+		emit(stem)
+	}
+
+	forms := []affixedForm{{word: stem, prefixes: a.prefixFlags(flags, false)}}
+	crossPrefixes := a.prefixFlags(flags, true)
+	for _, flag := range flags {
+		class := a.classes[flag]
+		if class == nil || class.prefix {
+			continue
+		}
+		for _, rule := range class.rules {
+			word, ok := rule.applySuffix(stem)
+			if !ok {
+				continue
+			}
+			if !hasFlag(rule.flags, a.needAffix) {
+				emit(word)
+			}
+			prefixes := a.prefixFlags(rule.flags, false)
+			if class.cross {
+				prefixes = append(prefixes, crossPrefixes...)
+			}
+			forms = append(forms, affixedForm{word: word, prefixes: prefixes})
+
+			// Twofold suffixes: the continuation classes of the suffix
+			for _, next := range rule.flags {
+				nextClass := a.classes[next]
+				if nextClass == nil || nextClass.prefix {
+					continue
+				}
+				for _, nextRule := range nextClass.rules {
+					if twice, ok := nextRule.applySuffix(word); ok {
+						emit(twice)
+						if class.cross && nextClass.cross {
+							forms = append(forms, affixedForm{word: twice, prefixes: crossPrefixes})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, form := range forms {
+		for _, flag := range form.prefixes {
+			for _, rule := range a.classes[flag].rules {
+				if word, ok := rule.applyPrefix(form.word); ok {
+					emit(word)
+				}
+			}
+		}
+	}
+}
+
+// prefixFlags returns the flags naming prefix classes, only those allowing
+// cross products when crossOnly is set
+func (a *affixFile) prefixFlags(flags []string, crossOnly bool) []string {
+	var prefixes []string
+	for _, flag := range flags {
+		if class := a.classes[flag]; class != nil && class.prefix && (class.cross || !crossOnly) {
+			prefixes = append(prefixes, flag)
+		}
+	}
+	return prefixes
+}
+
+// applySuffix applies the rule to the end of word if its condition matches
+func (r affixRule) applySuffix(word string) (string, bool) {
+	runes := []rune(word)
+	if !strings.HasSuffix(word, r.strip) || len(word) <= len(r.strip) || !r.cond.matchEnd(runes) {
+		return "", false
+	}
+	return word[:len(word)-len(r.strip)] + r.add, true
+}
+
+// applyPrefix applies the rule to the start of word if its condition matches
+func (r affixRule) applyPrefix(word string) (string, bool) {
+	runes := []rune(word)
+	if !strings.HasPrefix(word, r.strip) || len(word) <= len(r.strip) || !r.cond.matchStart(runes) {
+		return "", false
+	}
+	return r.add + word[len(r.strip):], true
+}
+
+// condElem matches one character: any character, or one in or not in chars
+type condElem struct {
+	any    bool
+	negate bool
+	chars  string
+}
+
+func (e condElem) matches(r rune) bool {
+	if e.any {
+		return true
+	}
+	return strings.ContainsRune(e.chars, r) != e.negate
+}
+
+// condition is the simplified regular expression an affix rule requires at
+// the edge of the word it applies to, such as "[^aeiou]y"
+type condition []condElem
+
+// parseCondition parses an affix rule condition
+func parseCondition(s string) (condition, error) {
+	if s == "." {
+		return nil, nil
+	}
+	var cond condition
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			cond = append(cond, condElem{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated bracket in condition %q", s)
+			}
+			set := runes[i+1 : end]
+			elem := condElem{}
+			if len(set) > 0 && set[0] == '^' {
+				elem.negate = true
+				set = set[1:]
+			}
+			elem.chars = string(set)
+			cond = append(cond, elem)
+			i = end
+		default:
+			cond = append(cond, condElem{chars: string(runes[i])})
+		}
+	}
+	return cond, nil
+}
+
+// matchStart reports whether word starts with the condition
+func (c condition) matchStart(word []rune) bool {
+	if len(word) < len(c) {
+		return false
+	}
+	for i, e := range c {
+		if !e.matches(word[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEnd reports whether word ends with the condition
+func (c condition) matchEnd(word []rune) bool {
+	if len(word) < len(c) {
+		return false
+	}
+	offset := len(word) - len(c)
+	for i, e := range c {
+		if !e.matches(word[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasFlag(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
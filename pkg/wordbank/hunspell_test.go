@@ -0,0 +1,123 @@
+package wordbank
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestExpandHunspell(t *testing.T) {
+	aff := `SET UTF-8
+NEEDAFFIX X
+FORBIDDENWORD !
+
+# Prefixes
+PFX U Y 1
+PFX U 0 un .
+
+# Suffixes
+SFX S Y 3
+SFX S y ies [^aeiou]y
+SFX S 0 s [aeiou]y
+SFX S 0 s [^y]
+
+SFX D Y 2
+SFX D 0 ed [^ey]
+SFX D 0 d e
+
+SFX N N 1
+SFX N 0 ness/P .
+
+SFX P N 1
+SFX P 0 es s
+`
+
+	tests := []struct {
+		name     string
+		aff      string
+		dic      string
+		expected []string
+	}{
+		{
+			name:     "suffix conditions",
+			aff:      aff,
+			dic:      "3\nfly/S\nboy/S\ncat/S\n",
+			expected: []string{"boy", "boys", "cat", "cats", "flies", "fly"},
+		},
+		{
+			name:     "cross product",
+			aff:      aff,
+			dic:      "1\nlock/UD\n",
+			expected: []string{"lock", "locked", "unlock", "unlocked"},
+		},
+		{
+			name:     "twofold suffixes",
+			aff:      aff,
+			dic:      "1\nkind/N\n",
+			expected: []string{"kind", "kindness", "kindnesses"},
+		},
+		{
+			name:     "needaffix and forbidden",
+			aff:      aff,
+			dic:      "2\nbak/XD\nbaked/!\n",
+			expected: []string{"baked"},
+		},
+		{
+			name:     "morphological fields and escaped slash",
+			aff:      aff,
+			dic:      "2\ncat/S po:noun\nand\\/or\n",
+			expected: []string{"and/or", "cat", "cats"},
+		},
+		{
+			name: "long flags and aliases",
+			aff: `FLAG long
+AF 1
+AF AaBb
+SFX Aa Y 1
+SFX Aa 0 s .
+PFX Bb Y 1
+PFX Bb 0 re .
+`,
+			dic:      "1\nload/1\n",
+			expected: []string{"load", "loads", "reload", "reloads"},
+		},
+		{
+			name:     "legacy encoding",
+			aff:      "SET ISO8859-1\nSFX E Y 1\nSFX E 0 s .\n",
+			dic:      "1\ncaf\xe9/E\n",
+			expected: []string{"café", "cafés"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := ExpandHunspell([]byte(tt.dic), []byte(tt.aff))
+			if err != nil {
+				t.Fatalf("ExpandHunspell() error = %v", err)
+			}
+			sort.Strings(words)
+			if !reflect.DeepEqual(words, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, words)
+			}
+		})
+	}
+}
+
+func TestExpandHunspellErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		aff  string
+	}{
+		{"unterminated condition", "SFX S Y 1\nSFX S 0 s [^y\n"},
+		{"malformed rule", "SFX S Y 1\nSFX S 0\n"},
+		{"unknown encoding", "SET NOT-AN-ENCODING\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExpandHunspell([]byte("1\ncat/S\n"), []byte(tt.aff)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package wordbank
+
+import (
+	"iter"
+	"math/rand/v2"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Queries return at most limit words, or every match when limit is 0 or
+// less, in sorted order. Snapshots are sorted already and stop at the limit,
+// while word banks have to collect and sort every match first.
+
+// All returns the words of the word bank in no particular order. The word
+// bank can't be modified until the iteration is done.
+func (wb *WordBank) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		wb.mu.RLock()
+		defer wb.mu.RUnlock()
+		for word := range wb.words {
+			if !yield(word) {
+				return
+			}
+		}
+	}
+}
+
+// Prefix returns the words starting with prefix once normalized
+func (wb *WordBank) Prefix(prefix string, limit int) []string {
+	wb.mu.RLock()
+	prefix = wb.normalize(prefix)
+	wb.mu.RUnlock()
+	return collect(wb.All(), func(word string) bool { return strings.HasPrefix(word, prefix) }, limit, false)
+}
+
+// Match returns the words matching re
+func (wb *WordBank) Match(re *regexp.Regexp, limit int) []string {
+	return collect(wb.All(), re.MatchString, limit, false)
+}
+
+// Glob returns the words matching a shell pattern with *, ? and [...]
+func (wb *WordBank) Glob(pattern string, limit int) ([]string, error) {
+	match, err := globMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return collect(wb.All(), match, limit, false), nil
+}
+
+// Sample returns up to n words chosen at random
+func (wb *WordBank) Sample(n int) []string {
+	return sample(wb.All(), n)
+}
+
+// All returns the words of the snapshot in sorted order
+func (s *Snapshot) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(s.word(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Prefix returns the words starting with prefix, found by binary search.
+// Like Contains, it expects an already normalized prefix.
+func (s *Snapshot) Prefix(prefix string, limit int) []string {
+	var words []string
+	for i := sort.Search(s.Len(), func(i int) bool { return s.word(i) >= prefix }); i < s.Len(); i++ {
+		word := s.word(i)
+		if !strings.HasPrefix(word, prefix) || limit > 0 && len(words) == limit {
+			break
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// Match returns the words matching re
+func (s *Snapshot) Match(re *regexp.Regexp, limit int) []string {
+	return collect(s.All(), re.MatchString, limit, true)
+}
+
+// Glob returns the words matching a shell pattern with *, ? and [...]
+func (s *Snapshot) Glob(pattern string, limit int) ([]string, error) {
+	match, err := globMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return collect(s.All(), match, limit, true), nil
+}
+
+// Sample returns up to n words chosen at random
+func (s *Snapshot) Sample(n int) []string {
+	return sample(s.All(), n)
+}
+
+// collect returns the words of seq that match, stopping at limit right away
+// when seq is already sorted
+func collect(seq iter.Seq[string], match func(string) bool, limit int, sorted bool) []string {
+	var words []string
+	for word := range seq {
+		if sorted && limit > 0 && len(words) == limit {
+			break
+		}
+		if match(word) {
+			words = append(words, word)
+		}
+	}
+	if !sorted {
+		sort.Strings(words)
+		if limit > 0 && len(words) > limit {
+			words = words[:limit]
+		}
+	}
+	return words
+}
+
+// globMatcher checks pattern and returns a function matching words against it
+func globMatcher(pattern string) (func(string) bool, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(word string) bool {
+		matched, _ := path.Match(pattern, word)
+		return matched
+	}, nil
+}
+
+// sample picks up to n words of seq uniformly at random by reservoir sampling
+func sample(seq iter.Seq[string], n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	words := make([]string, 0, n)
+	seen := 0
+	for word := range seq {
+		seen++
+		if len(words) < n {
+			words = append(words, word)
+		} else if i := rand.IntN(seen); i < n {
+			words[i] = word
+		}
+	}
+	return words
+}
@@ -0,0 +1,78 @@
+package wordbank
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+func TestQueries(t *testing.T) {
+	wb := New()
+	for _, word := range []string{"car", "cart", "carton", "cat", "dog", "doge", "card"} {
+		wb.Add(word)
+	}
+	s := wb.Freeze()
+
+	type banks struct {
+		name string
+		// the query methods of the WordBank or Snapshot under test
+		prefix func(string, int) []string
+		match  func(*regexp.Regexp, int) []string
+		glob   func(string, int) ([]string, error)
+		sample func(int) []string
+		all    func() []string
+	}
+	for _, b := range []banks{
+		{"word bank", wb.Prefix, wb.Match, wb.Glob, wb.Sample, func() []string { return collect(wb.All(), func(string) bool { return true }, 0, false) }},
+		{"snapshot", s.Prefix, s.Match, s.Glob, s.Sample, func() []string { return collect(s.All(), func(string) bool { return true }, 0, true) }},
+	} {
+		t.Run(b.name, func(t *testing.T) {
+			tests := []struct {
+				name     string
+				got      []string
+				expected []string
+			}{
+				{"prefix", b.prefix("car", 0), []string{"car", "card", "cart", "carton"}},
+				{"prefix limit", b.prefix("car", 2), []string{"car", "card"}},
+				{"prefix none", b.prefix("z", 0), nil},
+				{"regex", b.match(regexp.MustCompile(`^d.g`), 0), []string{"dog", "doge"}},
+				{"regex limit", b.match(regexp.MustCompile(`t`), 2), []string{"cart", "carton"}},
+				{"all", b.all(), []string{"car", "card", "cart", "carton", "cat", "dog", "doge"}},
+			}
+			glob, err := b.glob("ca?", 0)
+			if err != nil {
+				t.Fatalf("Glob() error = %v", err)
+			}
+			tests = append(tests, struct {
+				name     string
+				got      []string
+				expected []string
+			}{"glob", glob, []string{"car", "cat"}})
+
+			for _, tt := range tests {
+				if !reflect.DeepEqual(tt.got, tt.expected) {
+					t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+				}
+			}
+
+			if _, err := b.glob("[ca", 0); err == nil {
+				t.Error("Expected error for a malformed glob pattern")
+			}
+
+			sampled := b.sample(3)
+			if len(sampled) != 3 {
+				t.Fatalf("Expected 3 sampled words, got %v", sampled)
+			}
+			sort.Strings(sampled)
+			for i, word := range sampled {
+				if !s.Contains(word) || i > 0 && sampled[i-1] == word {
+					t.Errorf("Expected distinct words of the bank, got %v", sampled)
+				}
+			}
+			if got := b.sample(100); len(got) != 7 {
+				t.Errorf("Expected the whole bank when sampling more words than it has, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package wordbank
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Op is how the words of a source are combined with those of the sources
+// before it
+type Op string
+
+const (
+	// OpUnion adds the source's words
+	OpUnion Op = "union"
+	// OpIntersect keeps only the words also in the source
+	OpIntersect Op = "intersect"
+	// OpSubtract removes the source's words
+	OpSubtract Op = "subtract"
+)
+
+// Source is a word list and how it is combined into a word bank. Location is
+// a local path, a file:// URL or an http(s) URL. Format is one of the Format
+// constants, detected from the location when empty. Affix is the location of
+// the Hunspell affix file when Location is a Hunspell dictionary.
+type Source struct {
+	Location string
+	Op       Op
+	Format   string
+	Affix    string
+}
+
+// DetectFormat returns the format of the source: Format if set, otherwise the
+// one its file extension, ignoring .gz, stands for
+func (s Source) DetectFormat() string {
+	if s.Format != "" {
+		return s.Format
+	}
+	if s.Affix != "" {
+		return FormatHunspell
+	}
+	switch path.Ext(strings.TrimSuffix(s.Location, ".gz")) {
+	case ".tsv":
+		return FormatTSV
+	case ".csv":
+		return FormatCSV
+	case ".dic":
+		return FormatHunspell
+	}
+	return FormatList
+}
+
+// AffixLocation returns the location of the source's Hunspell affix file:
+// Affix if set, otherwise the .aff file next to the .dic dictionary
+func (s Source) AffixLocation() string {
+	if s.Affix != "" {
+		return s.Affix
+	}
+	location := strings.TrimSuffix(s.Location, ".gz")
+	return strings.TrimSuffix(location, path.Ext(location)) + ".aff"
+}
+
+// Combine merges other into wb according to op, OpUnion if empty
+func (wb *WordBank) Combine(op Op, other *WordBank) error {
+	switch op {
+	case "", OpUnion:
+		wb.Union(other)
+	case OpIntersect:
+		wb.Intersect(other)
+	case OpSubtract:
+		wb.Subtract(other)
+	default:
+		return fmt.Errorf("unknown word bank operator %q", op)
+	}
+	return nil
+}
+
+// Origins of a word list's content
+const (
+	// OriginFile is a local file
+	OriginFile = "file"
+	// OriginNetwork is a fresh download
+	OriginNetwork = "network"
+	// OriginCache is a cached copy the server confirmed is still current
+	OriginCache = "cache"
+	// OriginStaleCache is a cached copy used because the download failed
+	OriginStaleCache = "stale-cache"
+)
+
+// List is the uncompressed content of a word list and where it came from
+type List struct {
+	Content      []byte
+	SHA256       string
+	ETag         string
+	LastModified string
+	Origin       string
+	// FetchError explains why a stale cached copy was used
+	FetchError string
+	// CacheError explains why a downloaded list could not be cached
+	CacheError string
+}
+
+// Reader reads word lists from local files and HTTP(S) URLs, transparently
+// decompressing gzipped content. It uses its own HTTP client rather than the
+// article fetcher, so word banks are not rate limited like articles.
+type Reader struct {
+	client    *http.Client
+	userAgent string
+	cache     *Cache
+}
+
+// NewReader creates a reader whose HTTP requests time out after timeout
+func NewReader(timeout time.Duration, userAgent string) *Reader {
+	return &Reader{
+		client:    &http.Client{Timeout: timeout},
+		userAgent: userAgent,
+	}
+}
+
+// SetCache makes the reader keep downloaded word lists in cache, revalidate
+// them on later reads, and fall back to them when downloading fails
+func (r *Reader) SetCache(cache *Cache) {
+	r.cache = cache
+}
+
+// Cache returns the reader's cache, nil if it has none
+func (r *Reader) Cache() *Cache {
+	return r.cache
+}
+
+// Read returns the uncompressed content at location
+func (r *Reader) Read(ctx context.Context, location string) (*List, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return r.readRemote(ctx, location)
+	}
+
+	file, err := os.Open(strings.TrimPrefix(location, "file://"))
+	if err != nil {
+		return nil, fmt.Errorf("error opening word list: %w", err)
+	}
+	defer file.Close()
+
+	content, err := decompress(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading word list %s: %w", location, err)
+	}
+	return newList(content, OriginFile), nil
+}
+
+// readRemote downloads the word list at url, going through the cache if set
+func (r *Reader) readRemote(ctx context.Context, url string) (*List, error) {
+	var cached *List
+	if r.cache != nil {
+		// A missing or corrupt cache entry is simply downloaded again
+		cached, _ = r.cache.Load(url)
+	}
+
+	list, err := r.download(ctx, url, cached)
+	if err != nil {
+		if cached == nil {
+			return nil, err
+		}
+		cached.Origin = OriginStaleCache
+		cached.FetchError = err.Error()
+		return cached, nil
+	}
+
+	if list.Origin == OriginNetwork && r.cache != nil {
+		if err := r.cache.Store(url, list); err != nil {
+			list.CacheError = err.Error()
+		}
+	}
+	return list, nil
+}
+
+// download fetches url, asking the server to confirm cached is current if set
+func (r *Reader) download(ctx context.Context, url string, cached *List) (*List, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching word list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		cached.Origin = OriginCache
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status code %d fetching word list", resp.StatusCode)
+	}
+
+	content, err := decompress(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading word list %s: %w", url, err)
+	}
+	list := newList(content, OriginNetwork)
+	list.ETag = resp.Header.Get("ETag")
+	list.LastModified = resp.Header.Get("Last-Modified")
+	return list, nil
+}
+
+// newList wraps content, recording its SHA-256
+func newList(content []byte, origin string) *List {
+	sum := sha256.Sum256(content)
+	return &List{
+		Content: content,
+		SHA256:  hex.EncodeToString(sum[:]),
+		Origin:  origin,
+	}
+}
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompress reads r, gunzipping it when it starts with the gzip magic bytes
+func decompress(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	}
+	return io.ReadAll(br)
+}
@@ -0,0 +1,146 @@
+package wordbank
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReaderRead(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "words.txt")
+	if err := os.WriteFile(plain, []byte("alpha\nbeta"), 0644); err != nil {
+		t.Fatalf("Failed to create word list: %v", err)
+	}
+	zipped := filepath.Join(dir, "words.txt.gz")
+	if err := os.WriteFile(zipped, gzipBytes(t, "gamma\ndelta"), 0644); err != nil {
+		t.Fatalf("Failed to create word list: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/words.gz":
+			w.Write(gzipBytes(t, "epsilon"))
+		case "/words.txt":
+			w.Write([]byte("zeta"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		location string
+		want     string
+		wantErr  bool
+	}{
+		{"Local File", plain, "alpha\nbeta", false},
+		{"Local Gzip", zipped, "gamma\ndelta", false},
+		{"File URL", "file://" + plain, "alpha\nbeta", false},
+		{"HTTP", server.URL + "/words.txt", "zeta", false},
+		{"HTTP Gzip", server.URL + "/words.gz", "epsilon", false},
+		{"HTTP Not Found", server.URL + "/missing", "", true},
+		{"Missing File", filepath.Join(dir, "missing.txt"), "", true},
+	}
+
+	r := NewReader(5*time.Second, "test-agent")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Read(context.Background(), tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(got.Content) != tt.want {
+				t.Errorf("Read() = %q, want %q", got.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	bank := func(words ...string) *WordBank {
+		wb := New()
+		for _, word := range words {
+			wb.Add(word)
+		}
+		return wb
+	}
+
+	tests := []struct {
+		op   Op
+		want []string
+	}{
+		{OpUnion, []string{"apple", "banana", "cherry"}},
+		{"", []string{"apple", "banana", "cherry"}},
+		{OpIntersect, []string{"banana"}},
+		{OpSubtract, []string{"apple"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.op), func(t *testing.T) {
+			wb := bank("apple", "banana")
+			if err := wb.Combine(tt.op, bank("banana", "cherry")); err != nil {
+				t.Fatalf("Combine() error = %v", err)
+			}
+			if wb.Len() != len(tt.want) {
+				t.Errorf("Combine() left %d words, want %v", wb.Len(), tt.want)
+			}
+			for _, word := range tt.want {
+				if !wb.Contains(word) {
+					t.Errorf("Combine() lost %q", word)
+				}
+			}
+		})
+	}
+
+	if err := New().Combine("xor", New()); err == nil {
+		t.Error("Expected error for unknown operator")
+	}
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatalf("Failed to gzip: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to gzip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSourceFormat(t *testing.T) {
+	tests := []struct {
+		source        Source
+		expected      string
+		expectedAffix string
+	}{
+		{Source{Location: "dictionaries/en_US.dic"}, FormatHunspell, "dictionaries/en_US.aff"},
+		{Source{Location: "https://example.com/de_DE.dic.gz"}, FormatHunspell, "https://example.com/de_DE.aff"},
+		{Source{Location: "words", Affix: "rules/en.aff"}, FormatHunspell, "rules/en.aff"},
+		{Source{Location: "words.tsv.gz"}, FormatTSV, ""},
+		{Source{Location: "https://example.com/words.csv"}, FormatCSV, ""},
+		{Source{Location: "words.txt"}, FormatList, ""},
+		{Source{Location: "https://example.com/words?format=csv", Format: FormatCSV}, FormatCSV, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.source.DetectFormat(); got != tt.expected {
+			t.Errorf("DetectFormat(%+v) = %q, want %q", tt.source, got, tt.expected)
+		}
+		if tt.expected != FormatHunspell {
+			continue
+		}
+		if got := tt.source.AffixLocation(); got != tt.expectedAffix {
+			t.Errorf("AffixLocation(%+v) = %q, want %q", tt.source, got, tt.expectedAffix)
+		}
+	}
+}
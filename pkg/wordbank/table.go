@@ -0,0 +1,131 @@
+package wordbank
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Word bank formats
+const (
+	// FormatList is one word per line
+	FormatList = "list"
+	// FormatTSV is a tab-separated table of words and their attributes
+	FormatTSV = "tsv"
+	// FormatCSV is a comma-separated table of words and their attributes
+	FormatCSV = "csv"
+	// FormatHunspell is a Hunspell .dic dictionary with its .aff affix file
+	FormatHunspell = "hunspell"
+)
+
+// Attributes are the optional columns of a tabular word bank entry
+type Attributes struct {
+	// Frequency is the word's expected frequency in any unit, such as
+	// occurrences per million words
+	Frequency    float64
+	PartOfSpeech string
+	Category     string
+}
+
+// Entry is a word of a tabular word bank with its attributes
+type Entry struct {
+	Word string
+	Attributes
+}
+
+// defaultColumns is the column order of a table without a header row
+var defaultColumns = []string{"word", "frequency", "pos", "category"}
+
+// columnNames maps the accepted header names to the canonical column names
+var columnNames = map[string]string{
+	"word":         "word",
+	"frequency":    "frequency",
+	"freq":         "frequency",
+	"pos":          "pos",
+	"partofspeech": "pos",
+	"category":     "category",
+}
+
+// ParseTable parses a tabular word bank whose fields are separated by comma.
+// Rows hold a word followed by its optional frequency, part of speech and
+// category, in that order unless the first row is a header naming the
+// columns, starting with "word". Lines starting with # are comments.
+func ParseTable(content []byte, comma rune) ([]Entry, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comma = comma
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = comma != '\t'
+
+	columns := defaultColumns
+	var entries []Entry
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading word bank table: %w", err)
+		}
+
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "word") {
+			columns = headerColumns(record)
+			continue
+		}
+
+		entry, err := parseEntry(columns, record)
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("word bank table line %d: %w", line, err)
+		}
+		if entry.Word != "" {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// headerColumns returns the canonical names of the columns of a header row,
+// "" for the ones that are not recognized
+func headerColumns(header []string) []string {
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+		columns[i] = columnNames[name]
+	}
+	return columns
+}
+
+// parseEntry maps the fields of a row to an entry
+func parseEntry(columns, record []string) (Entry, error) {
+	var entry Entry
+	for i, field := range record {
+		if i >= len(columns) {
+			break
+		}
+		field = strings.TrimSpace(field)
+		switch columns[i] {
+		case "word":
+			entry.Word = field
+		case "frequency":
+			if field == "" {
+				continue
+			}
+			frequency, err := strconv.ParseFloat(field, 64)
+			if err != nil || frequency < 0 {
+				return entry, fmt.Errorf("invalid frequency %q", field)
+			}
+			entry.Frequency = frequency
+		case "pos":
+			entry.PartOfSpeech = field
+		case "category":
+			entry.Category = field
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,76 @@
+package wordbank
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		comma    rune
+		expected []Entry
+		wantErr  bool
+	}{
+		{
+			name:    "positional columns",
+			content: "# word\tfrequency\tpos\tcategory\r\nbattery\t12.5\tnoun\ttech\r\nrun\t300\tverb\r\ngadget\r\n",
+			comma:   '\t',
+			expected: []Entry{
+				{Word: "battery", Attributes: Attributes{Frequency: 12.5, PartOfSpeech: "noun", Category: "tech"}},
+				{Word: "run", Attributes: Attributes{Frequency: 300, PartOfSpeech: "verb"}},
+				{Word: "gadget"},
+			},
+		},
+		{
+			name:    "header row",
+			content: "Word, Category, Part_Of_Speech, Source, Freq\nbattery, tech, noun, wiki, 12\n\"screen\", tech, noun, wiki,\n",
+			comma:   ',',
+			expected: []Entry{
+				{Word: "battery", Attributes: Attributes{Frequency: 12, PartOfSpeech: "noun", Category: "tech"}},
+				{Word: "screen", Attributes: Attributes{PartOfSpeech: "noun", Category: "tech"}},
+			},
+		},
+		{
+			name:    "invalid frequency",
+			content: "battery\tmany\n",
+			comma:   '\t',
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseTable([]byte(tt.content), tt.comma)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(entries, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, entries)
+			}
+		})
+	}
+}
+
+func TestSnapshotAttributes(t *testing.T) {
+	wb := New()
+	wb.AddEntry("Battery", Attributes{Category: "tech"})
+	wb.AddEntry("screen", Attributes{Category: "tech"})
+	wb.Add("gadget")
+
+	excluded := New()
+	excluded.Add("screen")
+	wb.Subtract(excluded)
+
+	s := wb.Freeze()
+	if attrs, ok := s.Attributes("battery"); !ok || attrs.Category != "tech" {
+		t.Errorf("Expected battery to be in category tech, got %+v, %v", attrs, ok)
+	}
+	if _, ok := s.Attributes("screen"); ok {
+		t.Error("Expected subtracted word to lose its attributes")
+	}
+	if _, ok := s.Attributes("gadget"); ok {
+		t.Error("Expected word from a plain list to have no attributes")
+	}
+}
@@ -7,24 +7,98 @@ import (
 
 type WordBank struct {
 	words map[string]struct{}
-	mu    sync.RWMutex
+	// attrs holds the attributes of words loaded from tabular word banks
+	attrs     map[string]Attributes
+	normalize func(string) string
+	mu        sync.RWMutex
 }
 
 func New() *WordBank {
 	return &WordBank{
-		words: make(map[string]struct{}),
+		words:     make(map[string]struct{}),
+		normalize: strings.ToLower,
 	}
 }
 
+// SetNormalizer replaces the function applied to words by Add and Contains.
+// It must be set before any words are added.
+func (wb *WordBank) SetNormalizer(normalize func(string) string) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	wb.normalize = normalize
+}
+
 func (wb *WordBank) Add(word string) {
 	wb.mu.Lock()
 	defer wb.mu.Unlock()
-	wb.words[strings.ToLower(word)] = struct{}{}
+	wb.words[wb.normalize(word)] = struct{}{}
+}
+
+// AddEntry adds a word together with its attributes
+func (wb *WordBank) AddEntry(word string, attrs Attributes) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	word = wb.normalize(word)
+	wb.words[word] = struct{}{}
+	if wb.attrs == nil {
+		wb.attrs = make(map[string]Attributes)
+	}
+	wb.attrs[word] = attrs
 }
 
 func (wb *WordBank) Contains(word string) bool {
 	wb.mu.RLock()
 	defer wb.mu.RUnlock()
-	_, exists := wb.words[strings.ToLower(word)]
+	_, exists := wb.words[wb.normalize(word)]
 	return exists
 }
+
+// Len returns the number of words in the word bank
+func (wb *WordBank) Len() int {
+	wb.mu.RLock()
+	defer wb.mu.RUnlock()
+	return len(wb.words)
+}
+
+// Union adds the words of other to the word bank
+func (wb *WordBank) Union(other *WordBank) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for word := range other.words {
+		wb.words[word] = struct{}{}
+	}
+	for word, attrs := range other.attrs {
+		if wb.attrs == nil {
+			wb.attrs = make(map[string]Attributes)
+		}
+		wb.attrs[word] = attrs
+	}
+}
+
+// Intersect removes the words that are not in other
+func (wb *WordBank) Intersect(other *WordBank) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for word := range wb.words {
+		if _, ok := other.words[word]; !ok {
+			delete(wb.words, word)
+			delete(wb.attrs, word)
+		}
+	}
+}
+
+// Subtract removes the words that are in other
+func (wb *WordBank) Subtract(other *WordBank) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for word := range other.words {
+		delete(wb.words, word)
+		delete(wb.attrs, word)
+	}
+}
@@ -0,0 +1,149 @@
+package wordbank
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotContains(t *testing.T) {
+	wb := New()
+	for _, word := range []string{"Apple", "banana", "band", "zebra", "éclair", "über"} {
+		wb.Add(word)
+	}
+	s := wb.Freeze()
+
+	if s.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", s.Len())
+	}
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"apple", true},
+		{"banana", true},
+		{"band", true},
+		{"ban", false},
+		{"bandana", false},
+		{"zebra", true},
+		{"zebras", false},
+		{"éclair", true},
+		{"über", true},
+		{"aardvark", false},
+		{"", false},
+		{"Apple", false}, // lookups are not normalized
+	}
+	for _, tt := range tests {
+		if got := s.Contains(tt.word); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+
+	// Later additions don't affect the snapshot
+	wb.Add("cherry")
+	if s.Contains("cherry") {
+		t.Error("Snapshot changed after adding to the word bank")
+	}
+}
+
+func TestSnapshotEmpty(t *testing.T) {
+	s := New().Freeze()
+	if s.Len() != 0 || s.Contains("word") || s.Contains("") {
+		t.Errorf("Empty snapshot Len() = %d, expected no words", s.Len())
+	}
+}
+
+func TestSnapshotMatchesWordBank(t *testing.T) {
+	wb, words := randomWordBank(20000)
+	s := wb.Freeze()
+	for _, word := range words {
+		if s.Contains(word) != wb.Contains(word) {
+			t.Fatalf("Contains(%q) differs between snapshot and word bank", word)
+		}
+	}
+}
+
+// Benchmarks compare lookups and memory of the locked map against a frozen
+// snapshot, at the size of the dwyl/english-words list
+
+const benchmarkWords = 470000
+
+func BenchmarkContains(b *testing.B) {
+	wb, words := randomWordBank(benchmarkWords)
+	s := wb.Freeze()
+
+	b.Run("map", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				wb.Contains(words[i%len(words)])
+			}
+		})
+	})
+	b.Run("snapshot", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				s.Contains(words[i%len(words)])
+			}
+		})
+	})
+}
+
+func BenchmarkMemory(b *testing.B) {
+	_, words := randomWordBank(benchmarkWords)
+
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reportHeap(b, func() interface{} {
+				// Copy the words so that their bytes count, as they do for the snapshot
+				wb := New()
+				for _, word := range words[:benchmarkWords] {
+					wb.Add(strings.Clone(word))
+				}
+				return wb
+			})
+		}
+	})
+	b.Run("snapshot", func(b *testing.B) {
+		wb := New()
+		for _, word := range words[:benchmarkWords] {
+			wb.Add(word)
+		}
+		for i := 0; i < b.N; i++ {
+			reportHeap(b, func() interface{} { return wb.Freeze() })
+		}
+	})
+}
+
+// reportHeap reports the heap retained by the value build returns
+func reportHeap(b *testing.B, build func() interface{}) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/(1<<20), "MB")
+}
+
+// randomWordBank returns a word bank of n random lowercase words, and a
+// lookup list holding those words followed by as many words not in the bank
+func randomWordBank(n int) (*WordBank, []string) {
+	rng := rand.New(rand.NewSource(1))
+	wb := New()
+	words := make([]string, 0, 2*n)
+	for len(words) < 2*n {
+		word := make([]byte, 3+rng.Intn(10))
+		for i := range word {
+			word[i] = byte('a' + rng.Intn(26))
+		}
+		words = append(words, fmt.Sprintf("%s%d", word, len(words)))
+	}
+	for _, word := range words[:n] {
+		wb.Add(word)
+	}
+	return wb, words
+}